@@ -0,0 +1,237 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// BarKind represents what closes each bar built by a BarAggregator.
+type BarKind string
+
+const (
+	// TimeBars cover fixed clock intervals since midnight.
+	TimeBars BarKind = "time"
+	// TickBars close once they contain a number of trades.
+	TickBars BarKind = "tick"
+	// VolumeBars close once they contain a number of shares traded.
+	VolumeBars BarKind = "volume"
+	// DollarBars close once they contain a dollar value traded.
+	DollarBars BarKind = "dollar"
+)
+
+// BarOptions configures the bars built by a BarAggregator.
+type BarOptions struct {
+	Kind BarKind
+	// Interval is the length of each bar for TimeBars.
+	Interval time.Duration
+	// Threshold is the number of trades for TickBars, the number of
+	// shares for VolumeBars and the number of whole dollars for
+	// DollarBars. A bar closes with the trade that reaches the
+	// threshold, so trades are never split across bars.
+	Threshold uint64
+	// IncludeHidden includes executions of hidden orders, which are
+	// left out by default, both as trades of their own and as the
+	// hidden part of trades that also executed against visible orders.
+	IncludeHidden bool
+	// IncludeAuction includes cross trades, which are left out by
+	// default.
+	IncludeAuction bool
+	// SkipHalts leaves out the trades between a trading halt and the
+	// resumption of trading. Tick, volume and dollar bars are also
+	// closed when trading is halted, while time bars keep covering
+	// their whole interval.
+	SkipHalts bool
+}
+
+// Bar is an OHLCV bar of trades.
+type Bar struct {
+	// Start and End are the interval covered by the bar for TimeBars,
+	// or the times of its first and last trades otherwise.
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	Open  Price         `json:"open"`
+	High  Price         `json:"high"`
+	Low   Price         `json:"low"`
+	Close Price         `json:"close"`
+	// Volume is the number of shares traded.
+	Volume uint64 `json:"volume"`
+	// Notional is the value traded, in the same units as Price.
+	Notional Price `json:"notional"`
+	VWAP     Price `json:"vwap"`
+	// Trades is the number of trades in the bar, see Trade.
+	Trades int `json:"trades"`
+}
+
+// add adds a trade to the bar.
+func (b *Bar) add(trade *Trade) {
+	low, high := trade.FirstPrice, trade.LastPrice
+	if low > high {
+		low, high = high, low
+	}
+	if b.Trades == 0 {
+		b.Open, b.High, b.Low = trade.FirstPrice, high, low
+	}
+	if high > b.High {
+		b.High = high
+	}
+	if low < b.Low {
+		b.Low = low
+	}
+	b.Close = trade.LastPrice
+	b.Volume += trade.Size
+	b.Notional += trade.Notional
+	if b.Volume > 0 {
+		b.VWAP = (b.Notional + Price(b.Volume/2)) / Price(b.Volume)
+	}
+	b.Trades++
+}
+
+// BarAggregator builds OHLCV bars from the trades of the LOBSTER
+// messages applied to it, grouping executions into trades with a
+// TradeAggregator. Bars with no trades are never returned.
+type BarAggregator struct {
+	options BarOptions
+	trades  *TradeAggregator
+	current *Bar
+	session *SessionState
+}
+
+// NewBarAggregator returns a BarAggregator building bars with the
+// given options.
+func NewBarAggregator(options BarOptions) (aggregator *BarAggregator, err error) {
+	switch options.Kind {
+	case TimeBars:
+		if options.Interval <= 0 {
+			err = fmt.Errorf("Error creating bar aggregator, time bars need a positive interval, not %s", options.Interval)
+			return
+		}
+	case TickBars, VolumeBars, DollarBars:
+		if options.Threshold == 0 {
+			err = fmt.Errorf("Error creating bar aggregator, %s bars need a positive threshold", options.Kind)
+			return
+		}
+	default:
+		err = fmt.Errorf("Error creating bar aggregator, unknown bar kind %q", options.Kind)
+		return
+	}
+	aggregator = &BarAggregator{
+		options: options,
+		trades:  NewTradeAggregator(),
+		session: NewSessionState(),
+	}
+	return
+}
+
+// Apply adds a LOBSTER message to the aggregator, returning the bars it
+// completes, if any. With SkipHalts, trading halts are followed with a
+// SessionState that is not strict.
+func (ba *BarAggregator) Apply(data LOBSTERData) (completed []*Bar, err error) {
+	var trade *Trade
+	if trade, err = ba.trades.Apply(data); err != nil {
+		return
+	}
+	if trade != nil {
+		completed = ba.addTrade(trade)
+	}
+	if !ba.options.SkipHalts {
+		return
+	}
+
+	wasHalted := ba.session.TradingHalted()
+	if _, err = ba.session.Apply(data); err != nil {
+		return
+	}
+	if ba.options.Kind != TimeBars && !wasHalted && ba.session.TradingHalted() && ba.current != nil {
+		completed = append(completed, ba.current)
+		ba.current = nil
+	}
+	return
+}
+
+// Flush returns the bars completed by the last trade and the bar
+// currently being built, if they have trades. It should be called
+// after the last message has been applied.
+func (ba *BarAggregator) Flush() (completed []*Bar) {
+	if trade := ba.trades.Flush(); trade != nil {
+		completed = ba.addTrade(trade)
+	}
+	if ba.current != nil {
+		completed = append(completed, ba.current)
+		ba.current = nil
+	}
+	return
+}
+
+// addTrade adds a trade to the current bar, returning the bars it
+// completes.
+func (ba *BarAggregator) addTrade(trade *Trade) (completed []*Bar) {
+	switch {
+	case trade.Kind == HiddenTrade && !ba.options.IncludeHidden:
+		return
+	case trade.Kind == AuctionTrade && !ba.options.IncludeAuction:
+		return
+	case ba.options.SkipHalts && ba.session.TradingHalted():
+		return
+	}
+	if trade.HiddenSize > 0 && !ba.options.IncludeHidden {
+		trade = trade.visiblePart()
+	}
+
+	if ba.options.Kind == TimeBars {
+		start := trade.Time - trade.Time%ba.options.Interval
+		if ba.current != nil && ba.current.Start != start {
+			completed = append(completed, ba.current)
+			ba.current = nil
+		}
+		if ba.current == nil {
+			ba.current = &Bar{Start: start, End: start + ba.options.Interval}
+		}
+		ba.current.add(trade)
+		return
+	}
+
+	if ba.current == nil {
+		ba.current = &Bar{Start: trade.Time}
+	}
+	ba.current.add(trade)
+	ba.current.End = trade.Time
+
+	var full bool
+	switch ba.options.Kind {
+	case TickBars:
+		full = uint64(ba.current.Trades) >= ba.options.Threshold
+	case VolumeBars:
+		full = ba.current.Volume >= ba.options.Threshold
+	case DollarBars:
+		full = ba.current.Notional >= Price(ba.options.Threshold)*PriceScale
+	}
+	if full {
+		completed = append(completed, ba.current)
+		ba.current = nil
+	}
+	return
+}
+
+// Bars reads every message of a LOBSTER message file and returns the
+// bars of its trades.
+func Bars(messages io.Reader, options BarOptions) (bars []*Bar, err error) {
+	var aggregator *BarAggregator
+	if aggregator, err = NewBarAggregator(options); err != nil {
+		return
+	}
+	messageReader := NewMessageReader(messages)
+	for messageReader.Next() {
+		var completed []*Bar
+		if completed, err = aggregator.Apply(messageReader.Data()); err != nil {
+			err = fmt.Errorf("Error building LOBSTER bars on line %d: %w", messageReader.Line(), err)
+			return
+		}
+		bars = append(bars, completed...)
+	}
+	if err = messageReader.Err(); err != nil {
+		return
+	}
+	bars = append(bars, aggregator.Flush()...)
+	return
+}
@@ -0,0 +1,63 @@
+package lobsterdata
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// haltedMessages trade before and after a trading halt within one
+// minute, with a trade during the halt that SkipHalts leaves out.
+const haltedMessages = "36000.1,4,1,100,1000000,-1\n" +
+	"36010,7,0,0,-1,-1\n" +
+	"36015,4,2,100,1020000,-1\n" +
+	"36020,7,0,0,1,-1\n" +
+	"36030,4,3,300,1010000,-1\n"
+
+// barsCases are bars built from haltedMessages.
+var barsCases = []struct {
+	name    string
+	options BarOptions
+	bars    []*Bar
+}{
+	{
+		name:    "time bars keep their interval",
+		options: BarOptions{Kind: TimeBars, Interval: time.Minute, SkipHalts: true},
+		bars: []*Bar{
+			{Start: 10 * time.Hour, End: 10*time.Hour + time.Minute, Open: 1000000, High: 1010000, Low: 1000000, Close: 1010000, Volume: 400, Notional: 403000000, VWAP: 1007500, Trades: 2},
+		},
+	},
+	{
+		name:    "tick bars close at the halt",
+		options: BarOptions{Kind: TickBars, Threshold: 10, SkipHalts: true},
+		bars: []*Bar{
+			{Start: 10*time.Hour + 100*time.Millisecond, End: 10*time.Hour + 100*time.Millisecond, Open: 1000000, High: 1000000, Low: 1000000, Close: 1000000, Volume: 100, Notional: 100000000, VWAP: 1000000, Trades: 1},
+			{Start: 10*time.Hour + 30*time.Second, End: 10*time.Hour + 30*time.Second, Open: 1010000, High: 1010000, Low: 1010000, Close: 1010000, Volume: 300, Notional: 303000000, VWAP: 1010000, Trades: 1},
+		},
+	},
+	{
+		name:    "halts included",
+		options: BarOptions{Kind: TimeBars, Interval: time.Minute},
+		bars: []*Bar{
+			{Start: 10 * time.Hour, End: 10*time.Hour + time.Minute, Open: 1000000, High: 1020000, Low: 1000000, Close: 1010000, Volume: 500, Notional: 505000000, VWAP: 1010000, Trades: 3},
+		},
+	},
+}
+
+func TestBars(t *testing.T) {
+	for _, c := range barsCases {
+		t.Run(c.name, func(t *testing.T) {
+			bars, err := Bars(strings.NewReader(haltedMessages), c.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(c.bars, bars) {
+				for _, bar := range bars {
+					t.Logf("%+v", *bar)
+				}
+				t.Fatalf("expected %d bars, got %d different bars", len(c.bars), len(bars))
+			}
+		})
+	}
+}
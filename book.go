@@ -0,0 +1,296 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RestingOrder is an order resting in a Book, as known from the
+// LOBSTER messages applied to it.
+type RestingOrder struct {
+	OrderID uint64 `json:"orderid"`
+	// Size is the remaining size of the order.
+	Size      uint64        `json:"size"`
+	Price     Price         `json:"price"`
+	Direction Side          `json:"side"`
+	Submitted time.Duration `json:"submitted"`
+}
+
+// bookSide holds the aggregated size at each price level of one side
+// of a Book.
+type bookSide struct {
+	// prices is sorted from the best price to the worst
+	prices []Price
+	sizes  map[Price]uint64
+	// better returns whether price a is better than price b on this
+	// side
+	better func(a, b Price) bool
+}
+
+func newBookSide(better func(a, b Price) bool) bookSide {
+	return bookSide{
+		sizes:  make(map[Price]uint64),
+		better: better,
+	}
+}
+
+// search returns the index of the price in the sorted prices, or the
+// index where it would be inserted if there is no such level.
+func (bs *bookSide) search(price Price) int {
+	return sort.Search(len(bs.prices), func(i int) bool {
+		return !bs.better(bs.prices[i], price)
+	})
+}
+
+// add adds size to the level at the given price, creating it if
+// needed.
+func (bs *bookSide) add(price Price, size uint64) {
+	if _, ok := bs.sizes[price]; !ok {
+		i := bs.search(price)
+		bs.prices = append(bs.prices, 0)
+		copy(bs.prices[i+1:], bs.prices[i:])
+		bs.prices[i] = price
+	}
+	bs.sizes[price] += size
+}
+
+// remove removes size from the level at the given price, removing the
+// level once it is empty. Levels never go below zero, since orders
+// submitted before the start of the data have unknown sizes.
+func (bs *bookSide) remove(price Price, size uint64) {
+	current, ok := bs.sizes[price]
+	if !ok {
+		return
+	}
+	if size < current {
+		bs.sizes[price] = current - size
+		return
+	}
+	delete(bs.sizes, price)
+	i := bs.search(price)
+	bs.prices = append(bs.prices[:i], bs.prices[i+1:]...)
+}
+
+// level returns the price level at the given index, where index 0 is
+// the best price.
+func (bs *bookSide) level(index int) (level PriceLevel, ok bool) {
+	if index < 0 || index >= len(bs.prices) {
+		return
+	}
+	price := bs.prices[index]
+	return PriceLevel{Price: price, Size: bs.sizes[price]}, true
+}
+
+// reset removes every level from the side.
+func (bs *bookSide) reset() {
+	bs.prices = bs.prices[:0]
+	bs.sizes = make(map[Price]uint64)
+}
+
+// Book is a limit order book reconstructed from LOBSTER messages. It
+// keeps the remaining size of every order it has seen submitted, and
+// the aggregated size at every price level.
+//
+// Orders that were resting before the start of the data are unknown
+// to the Book. Cancellations, deletions and executions of such orders
+// still reduce the size at their price level, which is only known if
+// the Book was seeded from an orderbook snapshot.
+type Book struct {
+	bids   bookSide
+	asks   bookSide
+	orders map[uint64]*RestingOrder
+}
+
+// NewBook returns an empty Book.
+func NewBook() *Book {
+	return &Book{
+		bids: newBookSide(func(a, b Price) bool {
+			return a > b
+		}),
+		asks: newBookSide(func(a, b Price) bool {
+			return a < b
+		}),
+		orders: make(map[uint64]*RestingOrder),
+	}
+}
+
+// Seed replaces the price levels of the Book with those of an
+// orderbook snapshot, typically the state of the book at the start of
+// the data. The orders already known to the Book are kept.
+func (b *Book) Seed(snapshot *OrderBookSnapshot) {
+	b.bids.reset()
+	b.asks.reset()
+	for _, level := range snapshot.Bids {
+		if !level.Empty() {
+			b.bids.add(level.Price, level.Size)
+		}
+	}
+	for _, level := range snapshot.Asks {
+		if !level.Empty() {
+			b.asks.add(level.Price, level.Size)
+		}
+	}
+}
+
+// side returns the side of the Book for a LOBSTER direction.
+func (b *Book) side(direction Side) (side *bookSide, err error) {
+	switch direction {
+	case Buy:
+		side = &b.bids
+	case Sell:
+		side = &b.asks
+	default:
+		err = fmt.Errorf("Error applying LOBSTER message to book, direction %d is neither 1 nor -1", direction)
+	}
+	return
+}
+
+// Apply updates the Book with a LOBSTER message. Hidden executions,
+// cross trades and trading halts do not change the Book.
+func (b *Book) Apply(data LOBSTERData) (err error) {
+	switch message := data.(type) {
+	case *LOBSTERSubmission:
+		return b.submit(message)
+	case *LOBSTERCancellation:
+		return b.reduce(message.OrderID, message.Size, message.Price, message.Direction, false)
+	case *LOBSTERDeletion:
+		return b.reduce(message.OrderID, message.Size, message.Price, message.Direction, true)
+	case *LOBSTERExecutionVisible:
+		return b.reduce(message.OrderID, message.Size, message.Price, message.Direction, false)
+	case *LOBSTERExecutionHidden, *LOBSTERCrossTrade, *LOBSTERTradingHalt:
+		return
+	}
+	err = fmt.Errorf("Error applying LOBSTER message to book, %T is not a LOBSTER message type", data)
+	return
+}
+
+// submit adds a newly submitted order to the Book.
+func (b *Book) submit(message *LOBSTERSubmission) (err error) {
+	var side *bookSide
+	if side, err = b.side(message.Direction); err != nil {
+		return
+	}
+
+	if _, ok := b.orders[message.OrderID]; ok {
+		err = fmt.Errorf("Error applying LOBSTER submission to book, order %d is already in the book", message.OrderID)
+		return
+	}
+
+	b.orders[message.OrderID] = &RestingOrder{
+		OrderID:   message.OrderID,
+		Size:      message.Size,
+		Price:     message.Price,
+		Direction: message.Direction,
+		Submitted: message.EventSinceMidnight,
+	}
+	side.add(message.Price, message.Size)
+	return
+}
+
+// reduce removes size from a resting order, or all of its remaining
+// size if remove is set.
+func (b *Book) reduce(orderID uint64, size uint64, price Price, direction Side, remove bool) (err error) {
+	var side *bookSide
+	if side, err = b.side(direction); err != nil {
+		return
+	}
+
+	order, ok := b.orders[orderID]
+	if !ok {
+		// The order was submitted before the start of the data, so
+		// only the level it rests at can be updated
+		side.remove(price, size)
+		return
+	}
+
+	if order.Price != price || order.Direction != direction {
+		err = fmt.Errorf("Error applying LOBSTER message to book, order %d rests at price %d side %s, not price %d side %s", orderID, order.Price, order.Direction, price, direction)
+		return
+	}
+	if size > order.Size {
+		err = fmt.Errorf("Error applying LOBSTER message to book, cannot remove %d from order %d with remaining size %d", size, orderID, order.Size)
+		return
+	}
+
+	if remove {
+		size = order.Size
+	}
+	side.remove(price, size)
+	if order.Size -= size; order.Size == 0 {
+		delete(b.orders, orderID)
+	}
+	return
+}
+
+// BestBid returns the best bid level. The second return value is false
+// if there are no bids.
+func (b *Book) BestBid() (level PriceLevel, ok bool) {
+	return b.bids.level(0)
+}
+
+// BestAsk returns the best ask level. The second return value is false
+// if there are no asks.
+func (b *Book) BestAsk() (level PriceLevel, ok bool) {
+	return b.asks.level(0)
+}
+
+// Bid returns the bid level at the given index, where index 0 is the
+// best bid. The second return value is false if the index is out of
+// range.
+func (b *Book) Bid(index int) (level PriceLevel, ok bool) {
+	return b.bids.level(index)
+}
+
+// Ask returns the ask level at the given index, where index 0 is the
+// best ask. The second return value is false if the index is out of
+// range.
+func (b *Book) Ask(index int) (level PriceLevel, ok bool) {
+	return b.asks.level(index)
+}
+
+// BidLevels returns the number of bid price levels in the Book.
+func (b *Book) BidLevels() int {
+	return len(b.bids.prices)
+}
+
+// AskLevels returns the number of ask price levels in the Book.
+func (b *Book) AskLevels() int {
+	return len(b.asks.prices)
+}
+
+// Order returns the resting order with the given OrderID. The second
+// return value is false if the order is not known to be resting in the
+// Book.
+func (b *Book) Order(orderID uint64) (order RestingOrder, ok bool) {
+	var resting *RestingOrder
+	if resting, ok = b.orders[orderID]; ok {
+		order = *resting
+	}
+	return
+}
+
+// NumOrders returns the number of known orders resting in the Book.
+func (b *Book) NumOrders() int {
+	return len(b.orders)
+}
+
+// Snapshot returns the first levels price levels of each side of the
+// Book, in the same form as a row of a LOBSTER orderbook file.
+func (b *Book) Snapshot(levels int) *OrderBookSnapshot {
+	snapshot := &OrderBookSnapshot{
+		Levels: levels,
+		Asks:   make([]PriceLevel, levels),
+		Bids:   make([]PriceLevel, levels),
+	}
+	for i := 0; i < levels; i++ {
+		var ok bool
+		if snapshot.Asks[i], ok = b.asks.level(i); !ok {
+			snapshot.Asks[i] = PriceLevel{Price: DummyAskPrice}
+		}
+		if snapshot.Bids[i], ok = b.bids.level(i); !ok {
+			snapshot.Bids[i] = PriceLevel{Price: DummyBidPrice}
+		}
+	}
+	return snapshot
+}
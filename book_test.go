@@ -0,0 +1,190 @@
+package lobsterdata
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// bookCases are message sequences applied to a Book, and the state the
+// Book must be in afterwards. Every message but the last must apply
+// without an error.
+var bookCases = []struct {
+	name string
+	// seed is an orderbook row the Book is seeded from, if set, before
+	// the message at index seedAt
+	seed     string
+	seedAt   int
+	messages string
+	// snapshot is the orderbook row expected from Snapshot
+	snapshot string
+	// orders are the orders expected to rest in the Book, and gone the
+	// orderids expected not to
+	orders []RestingOrder
+	gone   []uint64
+	// err is whether the last message returns an error
+	err bool
+}{
+	{
+		name: "price sorting",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,2,200,1020000,1\n" +
+			"34200.3,1,3,300,1010000,1\n" +
+			"34200.4,1,4,400,1050000,-1\n" +
+			"34200.5,1,5,500,1030000,-1\n" +
+			"34200.6,1,6,600,1040000,-1\n",
+		snapshot: "1030000,500,1020000,200,1040000,600,1010000,300,1050000,400,1000000,100",
+		orders: []RestingOrder{
+			{OrderID: 2, Size: 200, Price: 1020000, Direction: Buy, Submitted: 34200200000000},
+			{OrderID: 6, Size: 600, Price: 1040000, Direction: Sell, Submitted: 34200600000000},
+		},
+	},
+	{
+		name: "orders at one level",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,2,200,1000000,1\n" +
+			"34200.3,1,3,300,1010000,-1\n" +
+			"34200.4,1,4,400,1010000,-1\n",
+		snapshot: "1010000,700,1000000,300,9999999999,0,-9999999999,0",
+	},
+	{
+		name: "partial fill then deletion",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,2,50,990000,1\n" +
+			"34200.3,4,1,30,1000000,1\n" +
+			"34200.4,2,1,20,1000000,1\n" +
+			"34200.5,3,1,50,1000000,1\n",
+		snapshot: "9999999999,0,990000,50,9999999999,0,-9999999999,0",
+		orders: []RestingOrder{
+			{OrderID: 2, Size: 50, Price: 990000, Direction: Buy, Submitted: 34200200000000},
+		},
+		gone: []uint64{1},
+	},
+	{
+		name: "partial fill",
+		messages: "34200.1,1,1,100,1000000,-1\n" +
+			"34200.2,4,1,30,1000000,-1\n",
+		snapshot: "1000000,70,-9999999999,0",
+		orders: []RestingOrder{
+			{OrderID: 1, Size: 70, Price: 1000000, Direction: Sell, Submitted: 34200100000000},
+		},
+	},
+	{
+		name: "level removal on each side",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,2,100,990000,1\n" +
+			"34200.3,1,3,100,1010000,-1\n" +
+			"34200.4,1,4,100,1020000,-1\n" +
+			"34200.5,4,1,100,1000000,1\n" +
+			"34200.6,3,3,100,1010000,-1\n",
+		snapshot: "1020000,100,990000,100,9999999999,0,-9999999999,0",
+		gone:     []uint64{1, 3},
+	},
+	{
+		name: "hidden executions and halts",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,5,0,500,1000000,1\n" +
+			"34200.3,6,7,500,1000000,-1\n" +
+			"34200.4,7,0,0,-1,-1\n",
+		snapshot: "9999999999,0,1000000,100",
+	},
+	{
+		name:     "unknown orders reduce seeded levels",
+		seed:     "1010000,500,1000000,400,1020000,300,990000,200",
+		messages: "34200.1,2,8,100,1010000,-1\n" + "34200.2,4,9,400,1000000,1\n",
+		snapshot: "1010000,400,990000,200,1020000,300,-9999999999,0",
+		gone:     []uint64{8, 9},
+	},
+	{
+		name:     "unknown orders never go below zero",
+		seed:     "1010000,500,1000000,400",
+		messages: "34200.1,3,8,900,1010000,-1\n" + "34200.2,2,9,100,1050000,-1\n",
+		snapshot: "9999999999,0,1000000,400",
+	},
+	{
+		name: "known orders survive seeding",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,2,1,40,1000000,1\n",
+		seed:     "1010000,500,1000000,400",
+		seedAt:   1,
+		snapshot: "1010000,500,1000000,360",
+		orders: []RestingOrder{
+			{OrderID: 1, Size: 60, Price: 1000000, Direction: Buy, Submitted: 34200100000000},
+		},
+	},
+	{
+		name: "duplicate submission",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,1,100,1000000,1\n",
+		snapshot: "9999999999,0,1000000,100",
+		err:      true,
+	},
+	{
+		name: "cancellation larger than order",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,2,1,101,1000000,1\n",
+		snapshot: "9999999999,0,1000000,100",
+		err:      true,
+	},
+	{
+		name: "execution at another price",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,4,1,10,1010000,1\n",
+		snapshot: "9999999999,0,1000000,100",
+		err:      true,
+	},
+}
+
+// orderBookRow parses a row of an orderbook file, inferring its levels.
+func orderBookRow(t *testing.T, row string) *OrderBookSnapshot {
+	t.Helper()
+	snapshot := &OrderBookSnapshot{}
+	if err := snapshot.UnmarshalCsvLOBSTER(strings.Split(row, ",")); err != nil {
+		t.Fatalf("invalid orderbook row %q: %v", row, err)
+	}
+	return snapshot
+}
+
+func TestBook(t *testing.T) {
+	for _, c := range bookCases {
+		t.Run(c.name, func(t *testing.T) {
+			book := NewBook()
+			messageReader := NewMessageReader(strings.NewReader(c.messages))
+			var messages []LOBSTERData
+			for messageReader.Next() {
+				messages = append(messages, messageReader.Data())
+			}
+			if err := messageReader.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			var err error
+			for i, message := range messages {
+				if c.seed != "" && i == c.seedAt {
+					book.Seed(orderBookRow(t, c.seed))
+				}
+				if err = book.Apply(message); err != nil && i < len(messages)-1 {
+					t.Fatalf("message %d: %v", i+1, err)
+				}
+			}
+			if (err != nil) != c.err {
+				t.Fatalf("expected error %t, got %v", c.err, err)
+			}
+
+			expected := orderBookRow(t, c.snapshot)
+			if actual := book.Snapshot(expected.Levels); !reflect.DeepEqual(expected, actual) {
+				t.Fatalf("expected snapshot %+v, got %+v", expected, actual)
+			}
+			for _, order := range c.orders {
+				if actual, ok := book.Order(order.OrderID); !ok || actual != order {
+					t.Errorf("expected order %+v, got %+v (%t)", order, actual, ok)
+				}
+			}
+			for _, orderID := range c.gone {
+				if actual, ok := book.Order(orderID); ok {
+					t.Errorf("expected no order %d, got %+v", orderID, actual)
+				}
+			}
+		})
+	}
+}
@@ -13,48 +13,45 @@ type LOBSTERCancellation struct {
 	EventSinceMidnight time.Duration `json:"timesincemidnight"`
 	OrderID            uint64        `json:"orderid"`
 	Size               uint64        `json:"size"`
-	Price              uint64        `json:"price"`
-	Direction          int64         `json:"side"`
+	Price              Price         `json:"price"`
+	Direction          Side          `json:"side"`
 }
 
 // UnmarshalCsvLOBSTER unmarshals a list of strings into a
 // LOBSTERCancellation, given they are parsed from encoding/csv.
 func (lc *LOBSTERCancellation) UnmarshalCsvLOBSTER(eventFields []string) (err error) {
 	if len(eventFields) != 6 {
-		err = fmt.Errorf("Error unmarshalling LOBSTER line, data does not have 6 columns")
+		err = columnCountError(Cancellation, len(eventFields), 6)
 		return
 	}
 
 	if Event(eventFields[1]) != Cancellation {
-		err = fmt.Errorf("Trying to unmarshal a LOBSTER cancellation from an event that is not a cancellation is invalid")
+		err = messageFieldError(Cancellation, eventFields, 1, ErrEventType)
 		return
 	}
 
-	// Adding a "seconds" to the first field because we want to parse
-	// it as a duration
-	eventFields[0] += "s"
-	if lc.EventSinceMidnight, err = time.ParseDuration(eventFields[0]); err != nil {
-		err = fmt.Errorf("Error parsing the time field in LOBSTER data as a duration: %s", err)
+	if lc.EventSinceMidnight, err = ParseTimestamp(eventFields[0]); err != nil {
+		err = messageFieldError(Cancellation, eventFields, 0, err)
 		return
 	}
 
 	if lc.OrderID, err = strconv.ParseUint(eventFields[2], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing orderid field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(Cancellation, eventFields, 2, err)
 		return
 	}
 
 	if lc.Size, err = strconv.ParseUint(eventFields[3], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing size field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(Cancellation, eventFields, 3, err)
 		return
 	}
 
-	if lc.Price, err = strconv.ParseUint(eventFields[4], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing price field in LOBSTER data as uint64: %s", err)
+	if lc.Price, err = parsePriceField(eventFields[4]); err != nil {
+		err = messageFieldError(Cancellation, eventFields, 4, err)
 		return
 	}
 
-	if lc.Direction, err = strconv.ParseInt(eventFields[5], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing direction field in LOBSTER data as int64: %s", err)
+	if lc.Direction, err = parseSideField(eventFields[5]); err != nil {
+		err = messageFieldError(Cancellation, eventFields, 5, err)
 		return
 	}
 	return
@@ -64,7 +61,7 @@ func (lc *LOBSTERCancellation) UnmarshalCsvLOBSTER(eventFields []string) (err er
 // that can be written using encoding/csv.
 func (lc *LOBSTERCancellation) MarshalCsvLOBSTER() (eventFields []string, err error) {
 	eventFields = make([]string, 6)
-	eventFields[0] = fmt.Sprintf("%f", lc.EventSinceMidnight.Seconds())
+	eventFields[0] = FormatTimestamp(lc.EventSinceMidnight, -1)
 	eventFields[1] = fmt.Sprintf("%s", Cancellation)
 	eventFields[2] = fmt.Sprintf("%d", lc.OrderID)
 	eventFields[3] = fmt.Sprintf("%d", lc.Size)
@@ -83,3 +80,55 @@ func (lc *LOBSTERCancellation) MarshalJSON() (jsonBytes []byte, err error) {
 		EventType:    Cancellation,
 	})
 }
+
+// UnmarshalJSON implements the JSONUnmarshaler interface for this
+// struct, reading the document written by MarshalJSON.
+func (lc *LOBSTERCancellation) UnmarshalJSON(jsonBytes []byte) (err error) {
+	// The event is decoded through a type without this method, so
+	// that it is not called recursively
+	type cancellation LOBSTERCancellation
+	var document struct {
+		TheMainEvent *cancellation `json:"event"`
+		EventType    Event         `json:"eventtype"`
+	}
+	document.TheMainEvent = (*cancellation)(lc)
+	if err = json.Unmarshal(jsonBytes, &document); err != nil {
+		return
+	}
+
+	if document.EventType != Cancellation {
+		err = fmt.Errorf("Error unmarshalling LOBSTER cancellation from JSON with event type %q: %w", document.EventType, ErrEventType)
+		return
+	}
+	return
+}
+
+// Type returns Cancellation, implementing LOBSTERMessage.
+func (lc *LOBSTERCancellation) Type() Event {
+	return Cancellation
+}
+
+// Time returns EventSinceMidnight, implementing LOBSTERMessage.
+func (lc *LOBSTERCancellation) Time() time.Duration {
+	return lc.EventSinceMidnight
+}
+
+// GetOrderID implements LOBSTERMessage.
+func (lc *LOBSTERCancellation) GetOrderID() uint64 {
+	return lc.OrderID
+}
+
+// GetSize implements LOBSTERMessage.
+func (lc *LOBSTERCancellation) GetSize() uint64 {
+	return lc.Size
+}
+
+// GetPrice implements LOBSTERMessage.
+func (lc *LOBSTERCancellation) GetPrice() Price {
+	return lc.Price
+}
+
+// Side implements LOBSTERMessage.
+func (lc *LOBSTERCancellation) Side() Side {
+	return lc.Direction
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/op/go-logging"
+	"github.com/rjected/lobsterdata"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	app         = kingpin.New("lobsterbars", "A LOBSTER message file to OHLCV bars tool.")
+	verbose     = app.Flag("verbose", "Verbose mode.").Short('v').Bool()
+	lobsterpath = app.Flag("path", "Path to LOBSTER message csv file").Required().File()
+	barsout     = app.Flag("output", "Path to output file, standard output if not given").String()
+	outformat   = app.Flag("format", "Output format, csv or json.").Default("csv").Enum("csv", "json")
+	kind        = app.Flag("kind", "Kind of bars, time, tick, volume or dollar.").Default("time").Enum("time", "tick", "volume", "dollar")
+	interval    = app.Flag("interval", "Length of time bars, such as 1m or 500ms.").Default("1m").Duration()
+	threshold   = app.Flag("threshold", "Trades, shares or whole dollars in each tick, volume or dollar bar.").Uint64()
+	hidden      = app.Flag("hidden", "Include executions of hidden orders.").Bool()
+	auction     = app.Flag("auction", "Include cross trades.").Bool()
+	skiphalts   = app.Flag("skiphalts", "Leave out trades while trading is halted.").Bool()
+	dollars     = app.Flag("dollars", "Write prices as decimal dollars instead of integers.").Bool()
+
+	log = logging.MustGetLogger("lobsterdata")
+	// Everything except the message has a custom color which is
+	// dependent on the log level.
+	format = logging.MustStringFormatter(
+		`%{color}%{time:15:04:05.000} %{shortfunc} ▶ %{level:.4s} %{id:03x}%{color:reset} %{message}`,
+	)
+)
+
+// BarList is the JSON document written by lobsterbars.
+type BarList struct {
+	Bars []*lobsterdata.Bar `json:"bars"`
+}
+
+// csvHeader is the first row of the csv output.
+var csvHeader = []string{"start", "end", "open", "high", "low", "close", "volume", "notional", "vwap", "trades"}
+
+// formatPrice formats a price for the csv output.
+func formatPrice(price lobsterdata.Price) string {
+	if *dollars {
+		return price.String()
+	}
+	return strconv.FormatInt(int64(price), 10)
+}
+
+// csvRecord returns the csv row of a bar.
+func csvRecord(bar *lobsterdata.Bar) []string {
+	return []string{
+		lobsterdata.FormatTimestamp(bar.Start, -1),
+		lobsterdata.FormatTimestamp(bar.End, -1),
+		formatPrice(bar.Open),
+		formatPrice(bar.High),
+		formatPrice(bar.Low),
+		formatPrice(bar.Close),
+		strconv.FormatUint(bar.Volume, 10),
+		formatPrice(bar.Notional),
+		formatPrice(bar.VWAP),
+		strconv.Itoa(bar.Trades),
+	}
+}
+
+func main() {
+	app.HelpFlag.Short('h')
+	app.Parse(os.Args[1:])
+
+	backend := logging.NewBackendFormatter(logging.NewLogBackend(os.Stderr, "", 0), format)
+	backendLeveled := logging.AddModuleLevel(backend)
+	backendLeveled.SetLevel(logging.WARNING, "")
+	if verbose != nil && *verbose {
+		backendLeveled.SetLevel(logging.INFO, "")
+	}
+	logging.SetBackend(backendLeveled)
+
+	var messageFile *os.File = *lobsterpath
+	defer messageFile.Close()
+
+	aggregator, err := lobsterdata.NewBarAggregator(lobsterdata.BarOptions{
+		Kind:           lobsterdata.BarKind(*kind),
+		Interval:       *interval,
+		Threshold:      *threshold,
+		IncludeHidden:  *hidden,
+		IncludeAuction: *auction,
+		SkipHalts:      *skiphalts,
+	})
+	if err != nil {
+		log.Critical(err)
+		os.Exit(1)
+	}
+
+	var output io.Writer = os.Stdout
+	if *barsout != "" {
+		var outputFile *os.File
+		if outputFile, err = os.Create(*barsout); err != nil {
+			log.Criticalf("Could not create output file: %s", err)
+			os.Exit(1)
+		}
+		defer outputFile.Close()
+		output = outputFile
+	}
+
+	// Bars are written as they complete for csv, but collected for
+	// json since they form a single document
+	barList := BarList{Bars: []*lobsterdata.Bar{}}
+	csvWriter := csv.NewWriter(output)
+	writeBars := func(bars []*lobsterdata.Bar) {
+		if *outformat == "json" {
+			barList.Bars = append(barList.Bars, bars...)
+			return
+		}
+		for _, bar := range bars {
+			csvWriter.Write(csvRecord(bar))
+		}
+	}
+	if *outformat == "csv" {
+		csvWriter.Write(csvHeader)
+	}
+
+	log.Infof("Building %s bars", *kind)
+	messageReader := lobsterdata.NewMessageReader(messageFile)
+	for messageReader.Next() {
+		var bars []*lobsterdata.Bar
+		if bars, err = aggregator.Apply(messageReader.Data()); err != nil {
+			log.Criticalf("Error building bars on line %d: %s", messageReader.Line(), err)
+			os.Exit(1)
+		}
+		writeBars(bars)
+	}
+	if err = messageReader.Err(); err != nil {
+		log.Criticalf("Error reading LOBSTER file: %s", err)
+		os.Exit(1)
+	}
+	writeBars(aggregator.Flush())
+	log.Infof("Read %d messages", messageReader.Line())
+
+	if *outformat == "json" {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "\t")
+		err = encoder.Encode(barList)
+	} else {
+		csvWriter.Flush()
+		err = csvWriter.Error()
+	}
+	if err != nil {
+		log.Criticalf("Error writing bars: %s", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/op/go-logging"
+	"github.com/rjected/lobsterdata"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	app         = kingpin.New("lobsterbench", "A benchmark of the LOBSTER message parsers.")
+	lobsterpath = app.Flag("path", "Path to LOBSTER message csv file").Required().String()
+	runs        = app.Flag("runs", "Number of times to parse the file with each parser.").Default("5").Int()
+
+	log = logging.MustGetLogger("lobsterdata")
+)
+
+// messageSource is the common interface of the readers being compared.
+type messageSource interface {
+	Next() bool
+	Err() error
+}
+
+// parser is a named way of parsing a message file held in memory.
+type parser struct {
+	name string
+	open func(data []byte) messageSource
+}
+
+var parsers = []parser{
+	{"csv.Reader + UnmarshalCsvLOBSTER", func(data []byte) messageSource {
+		return lobsterdata.NewMessageReader(bytes.NewReader(data))
+	}},
+	{"MessageScanner (buffered reader)", func(data []byte) messageSource {
+		return lobsterdata.NewMessageScanner(bytes.NewReader(data))
+	}},
+	{"MessageScanner (in memory)", func(data []byte) messageSource {
+		return lobsterdata.NewMessageScannerBytes(data)
+	}},
+	{"ParallelMessageReader (ordered)", func(data []byte) messageSource {
+		return lobsterdata.NewParallelMessageReader(bytes.NewReader(data), lobsterdata.ParallelOptions{})
+	}},
+	{"ParallelMessageReader (unordered)", func(data []byte) messageSource {
+		return lobsterdata.NewParallelMessageReader(bytes.NewReader(data), lobsterdata.ParallelOptions{Unordered: true})
+	}},
+}
+
+func main() {
+	app.HelpFlag.Short('h')
+	app.Parse(os.Args[1:])
+
+	// The whole file is read up front so that disk speed does not
+	// affect the results
+	data, err := ioutil.ReadFile(*lobsterpath)
+	if err != nil {
+		log.Criticalf("Could not read LOBSTER csv file: %s", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%-36s %12s %14s %14s %12s\n", "parser", "rows", "ns/row", "rows/s", "allocs/row")
+	for _, p := range parsers {
+		var rows uint64
+		var elapsed time.Duration
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		for run := 0; run < *runs; run++ {
+			start := time.Now()
+			source := p.open(data)
+			for source.Next() {
+				rows++
+			}
+			elapsed += time.Since(start)
+			if err = source.Err(); err != nil {
+				log.Criticalf("Error parsing LOBSTER csv file with %s: %s", p.name, err)
+				os.Exit(1)
+			}
+		}
+		runtime.ReadMemStats(&after)
+
+		if rows == 0 {
+			log.Critical("LOBSTER csv file has no rows")
+			os.Exit(1)
+		}
+		fmt.Printf("%-36s %12d %14.1f %14.0f %12.2f\n",
+			p.name,
+			rows/uint64(*runs),
+			float64(elapsed.Nanoseconds())/float64(rows),
+			float64(rows)/elapsed.Seconds(),
+			float64(after.Mallocs-before.Mallocs)/float64(rows),
+		)
+	}
+}
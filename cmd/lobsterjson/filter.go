@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rjected/lobsterdata"
+)
+
+// eventTypes are all of the LOBSTER event types, which are the only
+// values accepted by --types and --exclude-types.
+var eventTypes = []lobsterdata.Event{
+	lobsterdata.Submission,
+	lobsterdata.Cancellation,
+	lobsterdata.Deletion,
+	lobsterdata.ExecutionVisible,
+	lobsterdata.ExecutionHidden,
+	lobsterdata.CrossTrade,
+	lobsterdata.TradingHalt,
+}
+
+// Filter decides which messages are converted to JSON. Each condition
+// is only checked if it was set, and a message must meet all of them.
+type Filter struct {
+	types    map[lobsterdata.Event]bool
+	excluded map[lobsterdata.Event]bool
+	from     time.Duration
+	hasFrom  bool
+	to       time.Duration
+	hasTo    bool
+	orderIDs map[uint64]bool
+	minPrice lobsterdata.Price
+	hasMin   bool
+	maxPrice lobsterdata.Price
+	hasMax   bool
+	side     lobsterdata.Side
+}
+
+// FilterFlags are the raw values of the filtering flags.
+type FilterFlags struct {
+	Types        string
+	ExcludeTypes string
+	From         string
+	To           string
+	OrderIDs     string
+	MinPrice     string
+	MaxPrice     string
+	Side         string
+}
+
+// NewFilter parses the filtering flags into a Filter.
+func NewFilter(flags FilterFlags) (filter *Filter, err error) {
+	filter = &Filter{}
+	if filter.types, err = parseEvents(flags.Types); err != nil {
+		err = fmt.Errorf("Error parsing --types: %s", err)
+		return
+	}
+	if filter.excluded, err = parseEvents(flags.ExcludeTypes); err != nil {
+		err = fmt.Errorf("Error parsing --exclude-types: %s", err)
+		return
+	}
+	if flags.From != "" {
+		filter.hasFrom = true
+		if filter.from, err = parseTimeOfDay(flags.From); err != nil {
+			err = fmt.Errorf("Error parsing --from: %s", err)
+			return
+		}
+	}
+	if flags.To != "" {
+		filter.hasTo = true
+		if filter.to, err = parseTimeOfDay(flags.To); err != nil {
+			err = fmt.Errorf("Error parsing --to: %s", err)
+			return
+		}
+	}
+	if filter.orderIDs, err = parseOrderIDs(flags.OrderIDs); err != nil {
+		err = fmt.Errorf("Error parsing --orderids: %s", err)
+		return
+	}
+	if flags.MinPrice != "" {
+		filter.hasMin = true
+		if filter.minPrice, err = lobsterdata.ParsePrice(flags.MinPrice); err != nil {
+			err = fmt.Errorf("Error parsing --minprice: %s", err)
+			return
+		}
+	}
+	if flags.MaxPrice != "" {
+		filter.hasMax = true
+		if filter.maxPrice, err = lobsterdata.ParsePrice(flags.MaxPrice); err != nil {
+			err = fmt.Errorf("Error parsing --maxprice: %s", err)
+			return
+		}
+	}
+	if flags.Side != "" {
+		if err = filter.side.UnmarshalText([]byte(flags.Side)); err != nil {
+			err = fmt.Errorf("Error parsing --side: %s", err)
+			return
+		}
+	}
+	return
+}
+
+// Match returns whether a message meets every condition of the filter.
+// Messages without an orderid, price or side, such as trading halts,
+// never match a filter on them.
+func (f *Filter) Match(data lobsterdata.LOBSTERData) bool {
+	message, ok := data.(lobsterdata.LOBSTERMessage)
+	if !ok {
+		return false
+	}
+	switch {
+	case f.types != nil && !f.types[message.Type()]:
+		return false
+	case f.excluded[message.Type()]:
+		return false
+	case f.hasFrom && message.Time() < f.from:
+		return false
+	case f.hasTo && message.Time() >= f.to:
+		return false
+	case f.orderIDs != nil && !f.orderIDs[message.GetOrderID()]:
+		return false
+	case f.side != 0 && message.Side() != f.side:
+		return false
+	}
+	if message.Type() == lobsterdata.TradingHalt && (f.hasMin || f.hasMax) {
+		return false
+	}
+	if f.hasMin && message.GetPrice() < f.minPrice || f.hasMax && message.GetPrice() > f.maxPrice {
+		return false
+	}
+	return true
+}
+
+// Past returns whether a message is at or after the end of the time
+// window, so that no later message in the file can match.
+func (f *Filter) Past(data lobsterdata.LOBSTERData) bool {
+	message, ok := data.(lobsterdata.LOBSTERMessage)
+	return ok && f.hasTo && message.Time() >= f.to
+}
+
+// parseEvents parses a comma separated list of event types, returning
+// nil if the list is empty.
+func parseEvents(list string) (types map[lobsterdata.Event]bool, err error) {
+	if list == "" {
+		return
+	}
+	types = make(map[lobsterdata.Event]bool)
+	for _, field := range strings.Split(list, ",") {
+		event := lobsterdata.Event(strings.TrimSpace(field))
+		known := false
+		for _, e := range eventTypes {
+			known = known || e == event
+		}
+		if !known {
+			err = fmt.Errorf("unknown event type %q, expected 1 to 7", event)
+			return
+		}
+		types[event] = true
+	}
+	return
+}
+
+// parseOrderIDs parses a comma separated list of orderids, returning
+// nil if the list is empty.
+func parseOrderIDs(list string) (orderIDs map[uint64]bool, err error) {
+	if list == "" {
+		return
+	}
+	orderIDs = make(map[uint64]bool)
+	for _, field := range strings.Split(list, ",") {
+		var orderID uint64
+		if orderID, err = strconv.ParseUint(strings.TrimSpace(field), 10, 64); err != nil {
+			return
+		}
+		orderIDs[orderID] = true
+	}
+	return
+}
+
+// parseTimeOfDay parses a time of day, either as HH:MM or HH:MM:SS
+// with optional decimal seconds, or as decimal seconds after midnight
+// like the timestamps of a message file. The latest time of day is
+// 24:00, the midnight ending the day.
+func parseTimeOfDay(value string) (sinceMidnight time.Duration, err error) {
+	parts := strings.Split(value, ":")
+	if len(parts) == 1 {
+		if sinceMidnight, err = lobsterdata.ParseTimestamp(value); err == nil && sinceMidnight > 24*time.Hour {
+			sinceMidnight = 0
+			err = fmt.Errorf("invalid time of day %q, it is after midnight", value)
+		}
+		return
+	}
+	if len(parts) > 3 {
+		err = fmt.Errorf("invalid time of day %q, expected HH:MM, HH:MM:SS or seconds after midnight", value)
+		return
+	}
+
+	var hours, minutes int64
+	if hours, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return
+	}
+	if minutes, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return
+	}
+	var seconds time.Duration
+	if len(parts) == 3 {
+		if seconds, err = lobsterdata.ParseTimestamp(parts[2]); err != nil {
+			return
+		}
+	}
+	sinceMidnight = time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + seconds
+	if hours < 0 || hours > 24 || minutes < 0 || minutes >= 60 || seconds >= time.Minute || sinceMidnight > 24*time.Hour {
+		sinceMidnight = 0
+		err = fmt.Errorf("invalid time of day %q", value)
+	}
+	return
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// timeOfDayCases are times of day and the time since midnight
+// parseTimeOfDay must return for them, or ok false if it must return
+// an error.
+var timeOfDayCases = []struct {
+	value         string
+	sinceMidnight time.Duration
+	ok            bool
+}{
+	{"09:45", 9*time.Hour + 45*time.Minute, true},
+	{"09:45:30", 9*time.Hour + 45*time.Minute + 30*time.Second, true},
+	{"15:59:59.5", 16*time.Hour - 500*time.Millisecond, true},
+	{"23:59:59.999999999", 24*time.Hour - time.Nanosecond, true},
+	{"24:00", 24 * time.Hour, true},
+	{"24:00:00", 24 * time.Hour, true},
+	{"34200.5", 34200500 * time.Millisecond, true},
+	{"86400", 24 * time.Hour, true},
+	{"24:59", 0, false},
+	{"24:00:30", 0, false},
+	{"24:00:00.000000001", 0, false},
+	{"25:00", 0, false},
+	{"86400.000000001", 0, false},
+	{"09:60", 0, false},
+	{"09:45:60", 0, false},
+	{"-1:00", 0, false},
+	{"09:45:30:00", 0, false},
+	{"9h45", 0, false},
+	{"2562047:47", 0, false},
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	for _, c := range timeOfDayCases {
+		sinceMidnight, err := parseTimeOfDay(c.value)
+		if (err == nil) != c.ok {
+			t.Errorf("parseTimeOfDay(%q): expected ok %t, got error %v", c.value, c.ok, err)
+			continue
+		}
+		if sinceMidnight != c.sinceMidnight {
+			t.Errorf("parseTimeOfDay(%q): expected %s, got %s", c.value, c.sinceMidnight, sinceMidnight)
+		}
+	}
+}
@@ -1,10 +1,11 @@
 package main
 
 import (
-	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"io"
 	"os"
+	"time"
 
 	"github.com/op/go-logging"
 	"github.com/rjected/lobsterdata"
@@ -12,12 +13,23 @@ import (
 )
 
 var (
-	app         = kingpin.New("lobsterjson", "A LOBSTER data csv to json tool.")
-	verbose     = app.Flag("verbose", "Verbose mode.").Short('v').Bool()
-	lobsterpath = app.Flag("path", "Path to LOBSTER csv file").Required().File()
-	lobsterout  = app.Flag("output", "Path to output json file").String()
-	tostdout    = app.Flag("tostdout", "Send JSON to standard output.").Bool()
-	numrows     = app.Flag("numrows", "Number of rows to process.").Uint()
+	app          = kingpin.New("lobsterjson", "A LOBSTER data csv to json tool.")
+	verbose      = app.Flag("verbose", "Verbose mode.").Short('v').Bool()
+	lobsterpath  = app.Flag("path", "Path to LOBSTER csv file").Required().File()
+	lobsterout   = app.Flag("output", "Path to output json file").String()
+	tostdout     = app.Flag("tostdout", "Send JSON to standard output.").Bool()
+	numrows      = app.Flag("numrows", "Number of csv rows to read, including rows skipped by filters.").Uint()
+	dollars      = app.Flag("dollars", "Write prices as decimal dollars instead of integers.").Bool()
+	timeformat   = app.Flag("timeformat", "Also write absolute timestamps, as rfc3339nano or epochnanos.").Default("sincemidnight").Enum("sincemidnight", "rfc3339nano", "epochnanos")
+	tradingdate  = app.Flag("date", "Trading date of the file as YYYY-MM-DD, inferred from the filename if not given.").String()
+	types        = app.Flag("types", "Only process these comma separated event types, such as 4,5.").String()
+	excludetypes = app.Flag("exclude-types", "Skip these comma separated event types.").String()
+	fromtime     = app.Flag("from", "Skip events before this time of day, as HH:MM[:SS] or seconds after midnight.").String()
+	totime       = app.Flag("to", "Skip events at or after this time of day, as HH:MM[:SS] or seconds after midnight.").String()
+	orderids     = app.Flag("orderids", "Only process events for these comma separated orderids.").String()
+	minprice     = app.Flag("minprice", "Skip events priced below this many dollars.").String()
+	maxprice     = app.Flag("maxprice", "Skip events priced above this many dollars.").String()
+	side         = app.Flag("side", "Only process events on this side of the book, buy or sell.").Enum("buy", "sell")
 
 	log = logging.MustGetLogger("lobsterdata")
 	// Example format string. Everything except the message has a custom color
@@ -29,12 +41,9 @@ var (
 )
 
 type EventList struct {
-	Events []json.Marshaler `json:"events"`
+	Events []json.RawMessage `json:"events"`
 }
 
-// TODO: add filtering, for example a flag that says "only process
-// entries with this type
-
 func main() {
 	app.HelpFlag.Short('h')
 	app.Parse(os.Args[1:])
@@ -91,78 +100,75 @@ func main() {
 	}
 
 	var actualPath *os.File = *lobsterpath
-	var csvLine []string
+	var data lobsterdata.LOBSTERData
+	var eventJSON []byte
 	events := EventList{
-		Events: []json.Marshaler{},
+		Events: []json.RawMessage{},
+	}
+	jsonOptions := lobsterdata.JSONOptions{
+		DollarPrices: *dollars,
+	}
+	if *timeformat != "sincemidnight" {
+		jsonOptions.TimeFormat = lobsterdata.TimeFormat(*timeformat)
+		if *tradingdate != "" {
+			var date time.Time
+			if date, err = time.Parse("2006-01-02", *tradingdate); err != nil {
+				log.Criticalf("Could not parse trading date: %s", err)
+				return
+			}
+			jsonOptions.TradingDay, err = lobsterdata.NewExchangeTradingDay(date)
+		} else {
+			jsonOptions.TradingDay, err = lobsterdata.TradingDayFromFilename(actualPath.Name())
+		}
+		if err != nil {
+			log.Criticalf("Could not determine the trading date, pass --date: %s", err)
+			return
+		}
+	}
+
+	var filter *Filter
+	if filter, err = NewFilter(FilterFlags{
+		Types:        *types,
+		ExcludeTypes: *excludetypes,
+		From:         *fromtime,
+		To:           *totime,
+		OrderIDs:     *orderids,
+		MinPrice:     *minprice,
+		MaxPrice:     *maxprice,
+		Side:         *side,
+	}); err != nil {
+		log.Critical(err)
+		return
 	}
 
-	csvReader := csv.NewReader(actualPath)
-	lineNum := uint(0)
+	messageReader := lobsterdata.NewMessageReader(actualPath)
 
 	log.Info("Starting CSV read")
-	for csvLine, err = csvReader.Read(); err == nil; csvLine, err = csvReader.Read() {
-		lineNum++
-		switch lobsterdata.Event(csvLine[1]) {
-		case lobsterdata.Submission:
-			currSubmission := new(lobsterdata.LOBSTERSubmission)
-			if err = currSubmission.UnmarshalCsvLOBSTER(csvLine); err != nil {
-				log.Criticalf("Error unmarshalling csv line into submission: %s", err)
-				return
-			}
-			events.Events = append(events.Events, currSubmission)
-		case lobsterdata.Cancellation:
-			currCancellation := new(lobsterdata.LOBSTERCancellation)
-			if err = currCancellation.UnmarshalCsvLOBSTER(csvLine); err != nil {
-				log.Criticalf("Error unmarshalling csv line into cancellation: %s", err)
-				return
-			}
-			events.Events = append(events.Events, currCancellation)
-		case lobsterdata.Deletion:
-			currDeletion := new(lobsterdata.LOBSTERDeletion)
-			if err = currDeletion.UnmarshalCsvLOBSTER(csvLine); err != nil {
-				log.Criticalf("Error unmarshalling csv line into Deletion: %s", err)
-				return
-			}
-			events.Events = append(events.Events, currDeletion)
-		case lobsterdata.ExecutionVisible:
-			currExecutionVisible := new(lobsterdata.LOBSTERExecutionVisible)
-			if err = currExecutionVisible.UnmarshalCsvLOBSTER(csvLine); err != nil {
-				log.Criticalf("Error unmarshalling csv line into ExecutionVisible: %s", err)
-				return
-			}
-			events.Events = append(events.Events, currExecutionVisible)
-		case lobsterdata.ExecutionHidden:
-			currExecutionHidden := new(lobsterdata.LOBSTERExecutionHidden)
-			if err = currExecutionHidden.UnmarshalCsvLOBSTER(csvLine); err != nil {
-				log.Criticalf("Error unmarshalling csv line into ExecutionHidden: %s", err)
-				return
-			}
-			events.Events = append(events.Events, currExecutionHidden)
-		case lobsterdata.CrossTrade:
-			currCrossTrade := new(lobsterdata.LOBSTERCrossTrade)
-			if err = currCrossTrade.UnmarshalCsvLOBSTER(csvLine); err != nil {
-				log.Criticalf("Error unmarshalling csv line into CrossTrade: %s", err)
-				return
-			}
-			events.Events = append(events.Events, currCrossTrade)
-		case lobsterdata.TradingHalt:
-			currTradingHalt := new(lobsterdata.LOBSTERTradingHalt)
-			if err = currTradingHalt.UnmarshalCsvLOBSTER(csvLine); err != nil {
-				log.Criticalf("Error unmarshalling csv line into TradingHalt: %s", err)
+	for data, err = messageReader.Read(); err != io.EOF; data, err = messageReader.Read() {
+		if errors.Is(err, lobsterdata.ErrUnknownEvent) {
+			log.Errorf("Encountered invalid data in csv file on line %d", messageReader.Line())
+		} else if err != nil {
+			log.Criticalf("Error unmarshalling csv line: %s", err)
+			return
+		} else if filter.Past(data) {
+			log.Info("Reached the end of the time window")
+			break
+		} else if filter.Match(data) {
+			if eventJSON, err = lobsterdata.MarshalJSONWithOptions(data, jsonOptions); err != nil {
+				log.Criticalf("Error marshalling data into json: %s", err)
 				return
 			}
-			events.Events = append(events.Events, currTradingHalt)
-		default:
-			log.Error("Encountered invalid data in csv file on line %d", lineNum)
-			continue
+			events.Events = append(events.Events, eventJSON)
 		}
 
-		if numrows != nil && lineNum == *numrows {
+		// --numrows limits the rows read, whether or not they are
+		// converted
+		if *numrows > 0 && messageReader.Line() >= uint64(*numrows) {
 			log.Info("Done processing data!")
 			break
 		}
 	}
-	if numrows != nil && lineNum == 0 {
+	if numrows != nil && messageReader.Line() == 0 {
 		if err = jsonfile.Close(); err != nil {
 			log.Criticalf("Error closing json file after writing: %s", err)
 			return
@@ -174,11 +180,6 @@ func main() {
 		return
 	}
 
-	if err != nil && err != io.EOF {
-		log.Criticalf("Error after reading csv file is not EOF: %s", err)
-		return
-	}
-
 	log.Info("Done reading csv, closing csv file")
 	if err = actualPath.Close(); err != nil {
 		log.Criticalf("Error closing csv file: %s", err)
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/op/go-logging"
+	"github.com/rjected/lobsterdata"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	app           = kingpin.New("lobsterofi", "A LOBSTER order flow imbalance tool.")
+	verbose       = app.Flag("verbose", "Verbose mode.").Short('v').Bool()
+	messagepath   = app.Flag("path", "Path to LOBSTER message csv file").Required().File()
+	orderbookpath = app.Flag("orderbook", "Path to LOBSTER orderbook csv file, found from the message filename if not given").String()
+	levels        = app.Flag("levels", "Number of levels in the orderbook file, inferred if not given").Int()
+	ofilevels     = app.Flag("ofilevels", "Number of levels to compute the order flow imbalance for.").Default("1").Int()
+	interval      = app.Flag("interval", "Length of clock windows, such as 1s or 100ms.").Duration()
+	events        = app.Flag("events", "Number of messages in each event window.").Int()
+	ofiout        = app.Flag("output", "Path to output csv file, standard output if not given").String()
+
+	log = logging.MustGetLogger("lobsterdata")
+	// Everything except the message has a custom color which is
+	// dependent on the log level.
+	format = logging.MustStringFormatter(
+		`%{color}%{time:15:04:05.000} %{shortfunc} ▶ %{level:.4s} %{id:03x}%{color:reset} %{message}`,
+	)
+)
+
+// csvHeader returns the first row of the csv output.
+func csvHeader() []string {
+	header := []string{"start", "end", "events"}
+	for i := 1; i <= *ofilevels; i++ {
+		header = append(header, "ofi"+strconv.Itoa(i))
+	}
+	for i := 1; i <= *ofilevels; i++ {
+		header = append(header, "queueimbalance"+strconv.Itoa(i))
+	}
+	return header
+}
+
+// csvRecord returns the csv row of a window.
+func csvRecord(window *lobsterdata.OFIWindow) []string {
+	record := []string{
+		lobsterdata.FormatTimestamp(window.Start, -1),
+		lobsterdata.FormatTimestamp(window.End, -1),
+		strconv.Itoa(window.Events),
+	}
+	for _, ofi := range window.OFI {
+		record = append(record, strconv.FormatInt(ofi, 10))
+	}
+	for _, imbalance := range window.QueueImbalance {
+		record = append(record, strconv.FormatFloat(imbalance, 'f', -1, 64))
+	}
+	return record
+}
+
+func main() {
+	app.HelpFlag.Short('h')
+	app.Parse(os.Args[1:])
+
+	backend := logging.NewBackendFormatter(logging.NewLogBackend(os.Stderr, "", 0), format)
+	backendLeveled := logging.AddModuleLevel(backend)
+	backendLeveled.SetLevel(logging.WARNING, "")
+	if verbose != nil && *verbose {
+		backendLeveled.SetLevel(logging.INFO, "")
+	}
+	logging.SetBackend(backendLeveled)
+
+	var messageFile *os.File = *messagepath
+	defer messageFile.Close()
+
+	aggregator, err := lobsterdata.NewOFIAggregator(lobsterdata.OFIOptions{
+		Levels:   *ofilevels,
+		Interval: *interval,
+		Events:   *events,
+	})
+	if err != nil {
+		log.Criticalf("%s, pass either --interval or --events", err)
+		os.Exit(1)
+	}
+
+	if *orderbookpath == "" {
+		if *orderbookpath, err = lobsterdata.OrderBookPath(messageFile.Name()); err != nil {
+			log.Criticalf("Could not find orderbook file from message filename, pass --orderbook: %s", err)
+			os.Exit(1)
+		}
+	}
+
+	log.Infof("Opening orderbook file %s", *orderbookpath)
+	var orderbookFile *os.File
+	if orderbookFile, err = os.Open(*orderbookpath); err != nil {
+		log.Criticalf("Could not open orderbook file: %s", err)
+		os.Exit(1)
+	}
+	defer orderbookFile.Close()
+
+	var output io.Writer = os.Stdout
+	if *ofiout != "" {
+		var outputFile *os.File
+		if outputFile, err = os.Create(*ofiout); err != nil {
+			log.Criticalf("Could not create output csv file: %s", err)
+			os.Exit(1)
+		}
+		defer outputFile.Close()
+		output = outputFile
+	}
+
+	csvWriter := csv.NewWriter(output)
+	csvWriter.Write(csvHeader())
+
+	log.Info("Computing order flow imbalance")
+	pairedReader := lobsterdata.NewPairedReader(messageFile, orderbookFile, *levels)
+	for pairedReader.Next() {
+		message, ok := pairedReader.Message().(lobsterdata.LOBSTERMessage)
+		if !ok {
+			log.Criticalf("Unexpected message type %T on line %d", pairedReader.Message(), pairedReader.Line())
+			os.Exit(1)
+		}
+		if window := aggregator.Apply(message.Time(), pairedReader.OrderBook()); window != nil {
+			csvWriter.Write(csvRecord(window))
+		}
+	}
+	if err = pairedReader.Err(); err != nil {
+		log.Criticalf("Error reading LOBSTER files: %s", err)
+		os.Exit(1)
+	}
+	if window := aggregator.Flush(); window != nil {
+		csvWriter.Write(csvRecord(window))
+	}
+	log.Infof("Read %d messages", pairedReader.Line())
+
+	csvWriter.Flush()
+	if err = csvWriter.Error(); err != nil {
+		log.Criticalf("Error writing csv: %s", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/op/go-logging"
+	"github.com/rjected/lobsterdata"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	app           = kingpin.New("lobstervalidate", "A LOBSTER message and orderbook consistency checker.")
+	verbose       = app.Flag("verbose", "Verbose mode.").Short('v').Bool()
+	messagepath   = app.Flag("path", "Path to LOBSTER message csv file").Required().File()
+	orderbookpath = app.Flag("orderbook", "Path to LOBSTER orderbook csv file, found from the message filename if not given").String()
+	levels        = app.Flag("levels", "Number of levels in the orderbook file, inferred if not given").Int()
+	reportout     = app.Flag("output", "Path to output json report file, standard output if not given").String()
+
+	log = logging.MustGetLogger("lobsterdata")
+	// Everything except the message has a custom color which is
+	// dependent on the log level.
+	format = logging.MustStringFormatter(
+		`%{color}%{time:15:04:05.000} %{shortfunc} ▶ %{level:.4s} %{id:03x}%{color:reset} %{message}`,
+	)
+)
+
+// Report is the JSON document written by lobstervalidate.
+type Report struct {
+	Valid     bool                  `json:"valid"`
+	Anomalies []lobsterdata.Anomaly `json:"anomalies"`
+}
+
+func main() {
+	app.HelpFlag.Short('h')
+	app.Parse(os.Args[1:])
+
+	backend := logging.NewBackendFormatter(logging.NewLogBackend(os.Stderr, "", 0), format)
+	backendLeveled := logging.AddModuleLevel(backend)
+	backendLeveled.SetLevel(logging.WARNING, "")
+	if verbose != nil && *verbose {
+		backendLeveled.SetLevel(logging.INFO, "")
+	}
+	logging.SetBackend(backendLeveled)
+
+	var messageFile *os.File = *messagepath
+	defer messageFile.Close()
+
+	var err error
+	if *orderbookpath == "" {
+		if *orderbookpath, err = lobsterdata.OrderBookPath(messageFile.Name()); err != nil {
+			log.Criticalf("Could not find orderbook file from message filename, pass --orderbook: %s", err)
+			os.Exit(2)
+		}
+	}
+
+	log.Infof("Opening orderbook file %s", *orderbookpath)
+	var orderbookFile *os.File
+	if orderbookFile, err = os.Open(*orderbookpath); err != nil {
+		log.Criticalf("Could not open orderbook file: %s", err)
+		os.Exit(2)
+	}
+	defer orderbookFile.Close()
+
+	log.Info("Validating messages against orderbook")
+	report := Report{}
+	if report.Anomalies, err = lobsterdata.Validate(messageFile, orderbookFile, *levels); err != nil {
+		log.Criticalf("Error reading LOBSTER files: %s", err)
+		os.Exit(2)
+	}
+	report.Valid = len(report.Anomalies) == 0
+	if report.Anomalies == nil {
+		report.Anomalies = []lobsterdata.Anomaly{}
+	}
+	log.Infof("Found %d anomalies", len(report.Anomalies))
+
+	var reportOutput io.Writer = os.Stdout
+	if *reportout != "" {
+		var reportFile *os.File
+		if reportFile, err = os.Create(*reportout); err != nil {
+			log.Criticalf("Could not create output JSON file: %s", err)
+			os.Exit(2)
+		}
+		defer reportFile.Close()
+		reportOutput = reportFile
+	}
+
+	encoder := json.NewEncoder(reportOutput)
+	encoder.SetIndent("", "\t")
+	if err = encoder.Encode(report); err != nil {
+		log.Criticalf("Error writing json report: %s", err)
+		os.Exit(2)
+	}
+
+	if !report.Valid {
+		os.Exit(1)
+	}
+}
@@ -13,48 +13,45 @@ type LOBSTERCrossTrade struct {
 	EventSinceMidnight time.Duration `json:"timesincemidnight"`
 	OrderID            uint64        `json:"orderid"`
 	Size               uint64        `json:"size"`
-	Price              uint64        `json:"price"`
-	Direction          int64         `json:"side"`
+	Price              Price         `json:"price"`
+	Direction          Side          `json:"side"`
 }
 
 // UnmarshalCsvLOBSTER unmarshals a list of strings into a
 // LOBSTERCrossTrade, given they are parsed from encoding/csv.
 func (lt *LOBSTERCrossTrade) UnmarshalCsvLOBSTER(eventFields []string) (err error) {
 	if len(eventFields) != 6 {
-		err = fmt.Errorf("Error unmarshalling LOBSTER line, data does not have 6 columns")
+		err = columnCountError(CrossTrade, len(eventFields), 6)
 		return
 	}
 
 	if Event(eventFields[1]) != CrossTrade {
-		err = fmt.Errorf("Trying to unmarshal a LOBSTER cross trade from an event that is not a cross trade is invalid")
+		err = messageFieldError(CrossTrade, eventFields, 1, ErrEventType)
 		return
 	}
 
-	// Adding a "seconds" to the first field because we want to parse
-	// it as a duration
-	eventFields[0] += "s"
-	if lt.EventSinceMidnight, err = time.ParseDuration(eventFields[0]); err != nil {
-		err = fmt.Errorf("Error parsing the time field in LOBSTER data as a duration: %s", err)
+	if lt.EventSinceMidnight, err = ParseTimestamp(eventFields[0]); err != nil {
+		err = messageFieldError(CrossTrade, eventFields, 0, err)
 		return
 	}
 
 	if lt.OrderID, err = strconv.ParseUint(eventFields[2], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing orderid field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(CrossTrade, eventFields, 2, err)
 		return
 	}
 
 	if lt.Size, err = strconv.ParseUint(eventFields[3], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing size field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(CrossTrade, eventFields, 3, err)
 		return
 	}
 
-	if lt.Price, err = strconv.ParseUint(eventFields[4], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing price field in LOBSTER data as uint64: %s", err)
+	if lt.Price, err = parsePriceField(eventFields[4]); err != nil {
+		err = messageFieldError(CrossTrade, eventFields, 4, err)
 		return
 	}
 
-	if lt.Direction, err = strconv.ParseInt(eventFields[5], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing direction field in LOBSTER data as int64: %s", err)
+	if lt.Direction, err = parseSideField(eventFields[5]); err != nil {
+		err = messageFieldError(CrossTrade, eventFields, 5, err)
 		return
 	}
 	return
@@ -64,7 +61,7 @@ func (lt *LOBSTERCrossTrade) UnmarshalCsvLOBSTER(eventFields []string) (err erro
 // that can be written using encoding/csv.
 func (lt *LOBSTERCrossTrade) MarshalCsvLOBSTER() (eventFields []string, err error) {
 	eventFields = make([]string, 6)
-	eventFields[0] = fmt.Sprintf("%f", lt.EventSinceMidnight.Seconds())
+	eventFields[0] = FormatTimestamp(lt.EventSinceMidnight, -1)
 	eventFields[1] = fmt.Sprintf("%s", CrossTrade)
 	eventFields[2] = fmt.Sprintf("%d", lt.OrderID)
 	eventFields[3] = fmt.Sprintf("%d", lt.Size)
@@ -83,3 +80,55 @@ func (lt *LOBSTERCrossTrade) MarshalJSON() (jsonBytes []byte, err error) {
 		EventType:    CrossTrade,
 	})
 }
+
+// UnmarshalJSON implements the JSONUnmarshaler interface for this
+// struct, reading the document written by MarshalJSON.
+func (lt *LOBSTERCrossTrade) UnmarshalJSON(jsonBytes []byte) (err error) {
+	// The event is decoded through a type without this method, so
+	// that it is not called recursively
+	type crossTrade LOBSTERCrossTrade
+	var document struct {
+		TheMainEvent *crossTrade `json:"event"`
+		EventType    Event       `json:"eventtype"`
+	}
+	document.TheMainEvent = (*crossTrade)(lt)
+	if err = json.Unmarshal(jsonBytes, &document); err != nil {
+		return
+	}
+
+	if document.EventType != CrossTrade {
+		err = fmt.Errorf("Error unmarshalling LOBSTER cross trade from JSON with event type %q: %w", document.EventType, ErrEventType)
+		return
+	}
+	return
+}
+
+// Type returns CrossTrade, implementing LOBSTERMessage.
+func (lt *LOBSTERCrossTrade) Type() Event {
+	return CrossTrade
+}
+
+// Time returns EventSinceMidnight, implementing LOBSTERMessage.
+func (lt *LOBSTERCrossTrade) Time() time.Duration {
+	return lt.EventSinceMidnight
+}
+
+// GetOrderID implements LOBSTERMessage.
+func (lt *LOBSTERCrossTrade) GetOrderID() uint64 {
+	return lt.OrderID
+}
+
+// GetSize implements LOBSTERMessage.
+func (lt *LOBSTERCrossTrade) GetSize() uint64 {
+	return lt.Size
+}
+
+// GetPrice implements LOBSTERMessage.
+func (lt *LOBSTERCrossTrade) GetPrice() Price {
+	return lt.Price
+}
+
+// Side implements LOBSTERMessage.
+func (lt *LOBSTERCrossTrade) Side() Side {
+	return lt.Direction
+}
@@ -13,48 +13,45 @@ type LOBSTERDeletion struct {
 	EventSinceMidnight time.Duration `json:"timesincemidnight"`
 	OrderID            uint64        `json:"orderid"`
 	Size               uint64        `json:"size"`
-	Price              uint64        `json:"price"`
-	Direction          int64         `json:"side"`
+	Price              Price         `json:"price"`
+	Direction          Side          `json:"side"`
 }
 
 // UnmarshalCsvLOBSTER unmarshals a list of strings into a
 // LOBSTERDeletion, given they are parsed from encoding/csv.
 func (ld *LOBSTERDeletion) UnmarshalCsvLOBSTER(eventFields []string) (err error) {
 	if len(eventFields) != 6 {
-		err = fmt.Errorf("Error unmarshalling LOBSTER line, data does not have 6 columns")
+		err = columnCountError(Deletion, len(eventFields), 6)
 		return
 	}
 
 	if Event(eventFields[1]) != Deletion {
-		err = fmt.Errorf("Trying to unmarshal a LOBSTER deletion from an event that is not a deletion is invalid")
+		err = messageFieldError(Deletion, eventFields, 1, ErrEventType)
 		return
 	}
 
-	// Adding a "seconds" to the first field because we want to parse
-	// it as a duration
-	eventFields[0] += "s"
-	if ld.EventSinceMidnight, err = time.ParseDuration(eventFields[0]); err != nil {
-		err = fmt.Errorf("Error parsing the time field in LOBSTER data as a duration: %s", err)
+	if ld.EventSinceMidnight, err = ParseTimestamp(eventFields[0]); err != nil {
+		err = messageFieldError(Deletion, eventFields, 0, err)
 		return
 	}
 
 	if ld.OrderID, err = strconv.ParseUint(eventFields[2], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing orderid field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(Deletion, eventFields, 2, err)
 		return
 	}
 
 	if ld.Size, err = strconv.ParseUint(eventFields[3], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing size field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(Deletion, eventFields, 3, err)
 		return
 	}
 
-	if ld.Price, err = strconv.ParseUint(eventFields[4], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing price field in LOBSTER data as uint64: %s", err)
+	if ld.Price, err = parsePriceField(eventFields[4]); err != nil {
+		err = messageFieldError(Deletion, eventFields, 4, err)
 		return
 	}
 
-	if ld.Direction, err = strconv.ParseInt(eventFields[5], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing direction field in LOBSTER data as int64: %s", err)
+	if ld.Direction, err = parseSideField(eventFields[5]); err != nil {
+		err = messageFieldError(Deletion, eventFields, 5, err)
 		return
 	}
 	return
@@ -64,7 +61,7 @@ func (ld *LOBSTERDeletion) UnmarshalCsvLOBSTER(eventFields []string) (err error)
 // that can be written using encoding/csv.
 func (ld *LOBSTERDeletion) MarshalCsvLOBSTER() (eventFields []string, err error) {
 	eventFields = make([]string, 6)
-	eventFields[0] = fmt.Sprintf("%f", ld.EventSinceMidnight.Seconds())
+	eventFields[0] = FormatTimestamp(ld.EventSinceMidnight, -1)
 	eventFields[1] = fmt.Sprintf("%s", Deletion)
 	eventFields[2] = fmt.Sprintf("%d", ld.OrderID)
 	eventFields[3] = fmt.Sprintf("%d", ld.Size)
@@ -83,3 +80,55 @@ func (ld *LOBSTERDeletion) MarshalJSON() (jsonBytes []byte, err error) {
 		EventType:    Deletion,
 	})
 }
+
+// UnmarshalJSON implements the JSONUnmarshaler interface for this
+// struct, reading the document written by MarshalJSON.
+func (ld *LOBSTERDeletion) UnmarshalJSON(jsonBytes []byte) (err error) {
+	// The event is decoded through a type without this method, so
+	// that it is not called recursively
+	type deletion LOBSTERDeletion
+	var document struct {
+		TheMainEvent *deletion `json:"event"`
+		EventType    Event     `json:"eventtype"`
+	}
+	document.TheMainEvent = (*deletion)(ld)
+	if err = json.Unmarshal(jsonBytes, &document); err != nil {
+		return
+	}
+
+	if document.EventType != Deletion {
+		err = fmt.Errorf("Error unmarshalling LOBSTER deletion from JSON with event type %q: %w", document.EventType, ErrEventType)
+		return
+	}
+	return
+}
+
+// Type returns Deletion, implementing LOBSTERMessage.
+func (ld *LOBSTERDeletion) Type() Event {
+	return Deletion
+}
+
+// Time returns EventSinceMidnight, implementing LOBSTERMessage.
+func (ld *LOBSTERDeletion) Time() time.Duration {
+	return ld.EventSinceMidnight
+}
+
+// GetOrderID implements LOBSTERMessage.
+func (ld *LOBSTERDeletion) GetOrderID() uint64 {
+	return ld.OrderID
+}
+
+// GetSize implements LOBSTERMessage.
+func (ld *LOBSTERDeletion) GetSize() uint64 {
+	return ld.Size
+}
+
+// GetPrice implements LOBSTERMessage.
+func (ld *LOBSTERDeletion) GetPrice() Price {
+	return ld.Price
+}
+
+// Side implements LOBSTERMessage.
+func (ld *LOBSTERDeletion) Side() Side {
+	return ld.Direction
+}
@@ -0,0 +1,129 @@
+package lobsterdata
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrColumnCount is returned when a LOBSTER row does not have the
+	// number of columns its type requires.
+	ErrColumnCount = errors.New("wrong number of columns")
+
+	// ErrEventType is returned when unmarshalling a LOBSTER message
+	// row into a type that does not match its event type.
+	ErrEventType = errors.New("wrong event type")
+
+	// ErrUnknownEvent is returned when a LOBSTER message row has an
+	// event type that does not match any of the known Event values.
+	ErrUnknownEvent = errors.New("unknown event type")
+
+	// ErrInvalidValue is returned when a field parses correctly but
+	// has a value that is not allowed for its event type, such as a
+	// nonzero orderid on a hidden execution.
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// messageFields are the names of the columns of a LOBSTER message
+// row.
+var messageFields = [6]string{"time", "eventtype", "orderid", "size", "price", "direction"}
+
+// eventNames are the names of each event type used in error messages.
+var eventNames = map[Event]string{
+	Submission:       "submission",
+	Cancellation:     "cancellation",
+	Deletion:         "deletion",
+	ExecutionVisible: "visible execution",
+	ExecutionHidden:  "hidden execution",
+	CrossTrade:       "cross trade",
+	TradingHalt:      "trading halt",
+}
+
+// ParseError is returned for every failure to unmarshal a LOBSTER csv
+// row. Err is either one of the sentinel errors of this package or the
+// error returned while parsing the field.
+type ParseError struct {
+	// Line is the 1-based line of the row in its file, or 0 if the row
+	// was not read through one of the readers of this package.
+	Line uint64
+	// Event is the event type being unmarshalled, or empty for
+	// orderbook rows and rows of unknown type.
+	Event Event
+	// Column is the 1-based column of the field, or 0 if the error is
+	// not specific to one field.
+	Column int
+	Field  string
+	Value  string
+	// Columns is the number of columns the row has, and ExpectedColumns
+	// the number it should have, for ErrColumnCount errors.
+	// ExpectedColumns is 0 for orderbook rows whose number of levels
+	// is inferred, which may have any positive multiple of 4 columns.
+	Columns         int
+	ExpectedColumns int
+	Err             error
+}
+
+// Error returns a description of the error including its location.
+func (pe *ParseError) Error() string {
+	name, ok := eventNames[pe.Event]
+	if !ok {
+		name = "row"
+	}
+
+	var location string
+	if pe.Line > 0 {
+		location = fmt.Sprintf(" on line %d", pe.Line)
+	}
+
+	if pe.Column == 0 && errors.Is(pe.Err, ErrColumnCount) {
+		expected := "a positive multiple of 4"
+		if pe.ExpectedColumns > 0 {
+			expected = fmt.Sprintf("%d", pe.ExpectedColumns)
+		}
+		return fmt.Sprintf("Error unmarshalling LOBSTER %s%s: %s, found %d but expected %s", name, location, pe.Err, pe.Columns, expected)
+	}
+	if pe.Column == 0 {
+		return fmt.Sprintf("Error unmarshalling LOBSTER %s%s: %s", name, location, pe.Err)
+	}
+	return fmt.Sprintf("Error parsing %s field (column %d, value %q) of LOBSTER %s%s: %s", pe.Field, pe.Column, pe.Value, name, location, pe.Err)
+}
+
+// Unwrap returns the underlying error, so that ParseError works with
+// errors.Is and errors.As.
+func (pe *ParseError) Unwrap() error {
+	return pe.Err
+}
+
+// messageFieldError returns a ParseError for the field of a LOBSTER
+// message row at the given 0-based index.
+func messageFieldError(event Event, eventFields []string, index int, err error) *ParseError {
+	return &ParseError{
+		Event:  event,
+		Column: index + 1,
+		Field:  messageFields[index],
+		Value:  eventFields[index],
+		Err:    err,
+	}
+}
+
+// columnCountError returns a ParseError for a row with the wrong number
+// of columns.
+func columnCountError(event Event, columns int, expectedColumns int) *ParseError {
+	return &ParseError{
+		Event:           event,
+		Columns:         columns,
+		ExpectedColumns: expectedColumns,
+		Err:             ErrColumnCount,
+	}
+}
+
+// withLine sets the line of err if it is a ParseError, and otherwise
+// wraps it in an error that mentions the line.
+func withLine(err error, line uint64) error {
+	var parseError *ParseError
+	if errors.As(err, &parseError) {
+		parseError.Line = line
+		return err
+	}
+	return fmt.Errorf("Error reading LOBSTER csv on line %d: %w", line, err)
+}
@@ -9,56 +9,55 @@ import (
 
 // LOBSTERExecutionHidden is a struct that represents the hidden
 // execution event type from the LOBSTER data set.
+// Direction is the side of the resting order that was executed, see
+// AggressorSide.
 type LOBSTERExecutionHidden struct {
 	EventSinceMidnight time.Duration `json:"timesincemidnight"`
 	Size               uint64        `json:"size"`
-	Price              uint64        `json:"price"`
-	Direction          int64         `json:"side"`
+	Price              Price         `json:"price"`
+	Direction          Side          `json:"side"`
 }
 
 // UnmarshalCsvLOBSTER unmarshals a list of strings into a
 // LOBSTERExecutionHidden, given they are parsed from encoding/csv.
 func (lh *LOBSTERExecutionHidden) UnmarshalCsvLOBSTER(eventFields []string) (err error) {
 	if len(eventFields) != 6 {
-		err = fmt.Errorf("Error unmarshalling LOBSTER line, data does not have 6 columns")
+		err = columnCountError(ExecutionHidden, len(eventFields), 6)
 		return
 	}
 
 	if Event(eventFields[1]) != ExecutionHidden {
-		err = fmt.Errorf("Trying to unmarshal a LOBSTER hidden execution from an event that is not a hidden execution is invalid")
+		err = messageFieldError(ExecutionHidden, eventFields, 1, ErrEventType)
 		return
 	}
 
-	// Adding a "seconds" to the first field because we want to parse
-	// it as a duration
-	eventFields[0] += "s"
-	if lh.EventSinceMidnight, err = time.ParseDuration(eventFields[0]); err != nil {
-		err = fmt.Errorf("Error parsing the time field in LOBSTER data as a duration: %s", err)
+	if lh.EventSinceMidnight, err = ParseTimestamp(eventFields[0]); err != nil {
+		err = messageFieldError(ExecutionHidden, eventFields, 0, err)
 		return
 	}
 
 	var tmpOrderID uint64
 	if tmpOrderID, err = strconv.ParseUint(eventFields[2], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing orderid field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(ExecutionHidden, eventFields, 2, err)
 		return
 	}
 	if tmpOrderID != 0 {
-		err = fmt.Errorf("Error, orderid field on hidden executions should be 0")
+		err = messageFieldError(ExecutionHidden, eventFields, 2, ErrInvalidValue)
 		return
 	}
 
 	if lh.Size, err = strconv.ParseUint(eventFields[3], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing size field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(ExecutionHidden, eventFields, 3, err)
 		return
 	}
 
-	if lh.Price, err = strconv.ParseUint(eventFields[4], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing price field in LOBSTER data as uint64: %s", err)
+	if lh.Price, err = parsePriceField(eventFields[4]); err != nil {
+		err = messageFieldError(ExecutionHidden, eventFields, 4, err)
 		return
 	}
 
-	if lh.Direction, err = strconv.ParseInt(eventFields[5], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing direction field in LOBSTER data as int64: %s", err)
+	if lh.Direction, err = parseSideField(eventFields[5]); err != nil {
+		err = messageFieldError(ExecutionHidden, eventFields, 5, err)
 		return
 	}
 	return
@@ -68,7 +67,7 @@ func (lh *LOBSTERExecutionHidden) UnmarshalCsvLOBSTER(eventFields []string) (err
 // that can be written using encoding/csv.
 func (lh *LOBSTERExecutionHidden) MarshalCsvLOBSTER() (eventFields []string, err error) {
 	eventFields = make([]string, 6)
-	eventFields[0] = fmt.Sprintf("%f", lh.EventSinceMidnight.Seconds())
+	eventFields[0] = FormatTimestamp(lh.EventSinceMidnight, -1)
 	eventFields[1] = fmt.Sprintf("%s", ExecutionHidden)
 	eventFields[2] = "0"
 	eventFields[3] = fmt.Sprintf("%d", lh.Size)
@@ -87,3 +86,68 @@ func (lh *LOBSTERExecutionHidden) MarshalJSON() (jsonBytes []byte, err error) {
 		EventType:    ExecutionHidden,
 	})
 }
+
+// UnmarshalJSON implements the JSONUnmarshaler interface for this
+// struct, reading the document written by MarshalJSON.
+func (lh *LOBSTERExecutionHidden) UnmarshalJSON(jsonBytes []byte) (err error) {
+	// The event is decoded through a type without this method, so
+	// that it is not called recursively
+	type executionHidden LOBSTERExecutionHidden
+	var document struct {
+		TheMainEvent *executionHidden `json:"event"`
+		EventType    Event            `json:"eventtype"`
+	}
+	document.TheMainEvent = (*executionHidden)(lh)
+	if err = json.Unmarshal(jsonBytes, &document); err != nil {
+		return
+	}
+
+	if document.EventType != ExecutionHidden {
+		err = fmt.Errorf("Error unmarshalling LOBSTER hidden execution from JSON with event type %q: %w", document.EventType, ErrEventType)
+		return
+	}
+	return
+}
+
+// RestingSide returns the side of the hidden order that was executed,
+// which is what LOBSTER records in the direction column.
+func (lh *LOBSTERExecutionHidden) RestingSide() Side {
+	return lh.Direction
+}
+
+// AggressorSide returns the side of the marketable order that caused
+// the execution, which is the opposite of the side of the resting
+// order. A buy aggressor means a buyer initiated trade.
+func (lh *LOBSTERExecutionHidden) AggressorSide() Side {
+	return lh.Direction.Opposite()
+}
+
+// Type returns ExecutionHidden, implementing LOBSTERMessage.
+func (lh *LOBSTERExecutionHidden) Type() Event {
+	return ExecutionHidden
+}
+
+// Time returns EventSinceMidnight, implementing LOBSTERMessage.
+func (lh *LOBSTERExecutionHidden) Time() time.Duration {
+	return lh.EventSinceMidnight
+}
+
+// GetOrderID implements LOBSTERMessage.
+func (lh *LOBSTERExecutionHidden) GetOrderID() uint64 {
+	return 0
+}
+
+// GetSize implements LOBSTERMessage.
+func (lh *LOBSTERExecutionHidden) GetSize() uint64 {
+	return lh.Size
+}
+
+// GetPrice implements LOBSTERMessage.
+func (lh *LOBSTERExecutionHidden) GetPrice() Price {
+	return lh.Price
+}
+
+// Side implements LOBSTERMessage.
+func (lh *LOBSTERExecutionHidden) Side() Side {
+	return lh.Direction
+}
@@ -9,52 +9,51 @@ import (
 
 // LOBSTERExecutionVisible is a struct that represents the visible
 // execution event type from the LOBSTER data set.
+// Direction is the side of the resting order that was executed, see
+// AggressorSide.
 type LOBSTERExecutionVisible struct {
 	EventSinceMidnight time.Duration `json:"timesincemidnight"`
 	OrderID            uint64        `json:"orderid"`
 	Size               uint64        `json:"size"`
-	Price              uint64        `json:"price"`
-	Direction          int64         `json:"side"`
+	Price              Price         `json:"price"`
+	Direction          Side          `json:"side"`
 }
 
 // UnmarshalCsvLOBSTER unmarshals a list of strings into a
 // LOBSTERExecutionVisible, given they are parsed from encoding/csv.
 func (lv *LOBSTERExecutionVisible) UnmarshalCsvLOBSTER(eventFields []string) (err error) {
 	if len(eventFields) != 6 {
-		err = fmt.Errorf("Error unmarshalling LOBSTER line, data does not have 6 columns")
+		err = columnCountError(ExecutionVisible, len(eventFields), 6)
 		return
 	}
 
 	if Event(eventFields[1]) != ExecutionVisible {
-		err = fmt.Errorf("Trying to unmarshal a LOBSTER visible execution from an event that is not a visible execution is invalid")
+		err = messageFieldError(ExecutionVisible, eventFields, 1, ErrEventType)
 		return
 	}
 
-	// Adding a "seconds" to the first field because we want to parse
-	// it as a duration
-	eventFields[0] += "s"
-	if lv.EventSinceMidnight, err = time.ParseDuration(eventFields[0]); err != nil {
-		err = fmt.Errorf("Error parsing the time field in LOBSTER data as a duration: %s", err)
+	if lv.EventSinceMidnight, err = ParseTimestamp(eventFields[0]); err != nil {
+		err = messageFieldError(ExecutionVisible, eventFields, 0, err)
 		return
 	}
 
 	if lv.OrderID, err = strconv.ParseUint(eventFields[2], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing orderid field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(ExecutionVisible, eventFields, 2, err)
 		return
 	}
 
 	if lv.Size, err = strconv.ParseUint(eventFields[3], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing size field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(ExecutionVisible, eventFields, 3, err)
 		return
 	}
 
-	if lv.Price, err = strconv.ParseUint(eventFields[4], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing price field in LOBSTER data as uint64: %s", err)
+	if lv.Price, err = parsePriceField(eventFields[4]); err != nil {
+		err = messageFieldError(ExecutionVisible, eventFields, 4, err)
 		return
 	}
 
-	if lv.Direction, err = strconv.ParseInt(eventFields[5], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing direction field in LOBSTER data as int64: %s", err)
+	if lv.Direction, err = parseSideField(eventFields[5]); err != nil {
+		err = messageFieldError(ExecutionVisible, eventFields, 5, err)
 		return
 	}
 	return
@@ -64,7 +63,7 @@ func (lv *LOBSTERExecutionVisible) UnmarshalCsvLOBSTER(eventFields []string) (er
 // that can be written using encoding/csv.
 func (lv *LOBSTERExecutionVisible) MarshalCsvLOBSTER() (eventFields []string, err error) {
 	eventFields = make([]string, 6)
-	eventFields[0] = fmt.Sprintf("%f", lv.EventSinceMidnight.Seconds())
+	eventFields[0] = FormatTimestamp(lv.EventSinceMidnight, -1)
 	eventFields[1] = fmt.Sprintf("%s", ExecutionVisible)
 	eventFields[2] = fmt.Sprintf("%d", lv.OrderID)
 	eventFields[3] = fmt.Sprintf("%d", lv.Size)
@@ -83,3 +82,68 @@ func (lv *LOBSTERExecutionVisible) MarshalJSON() (jsonBytes []byte, err error) {
 		EventType:    ExecutionVisible,
 	})
 }
+
+// UnmarshalJSON implements the JSONUnmarshaler interface for this
+// struct, reading the document written by MarshalJSON.
+func (lv *LOBSTERExecutionVisible) UnmarshalJSON(jsonBytes []byte) (err error) {
+	// The event is decoded through a type without this method, so
+	// that it is not called recursively
+	type executionVisible LOBSTERExecutionVisible
+	var document struct {
+		TheMainEvent *executionVisible `json:"event"`
+		EventType    Event             `json:"eventtype"`
+	}
+	document.TheMainEvent = (*executionVisible)(lv)
+	if err = json.Unmarshal(jsonBytes, &document); err != nil {
+		return
+	}
+
+	if document.EventType != ExecutionVisible {
+		err = fmt.Errorf("Error unmarshalling LOBSTER visible execution from JSON with event type %q: %w", document.EventType, ErrEventType)
+		return
+	}
+	return
+}
+
+// RestingSide returns the side of the visible order that was executed,
+// which is what LOBSTER records in the direction column.
+func (lv *LOBSTERExecutionVisible) RestingSide() Side {
+	return lv.Direction
+}
+
+// AggressorSide returns the side of the marketable order that caused
+// the execution, which is the opposite of the side of the resting
+// order. A buy aggressor means a buyer initiated trade.
+func (lv *LOBSTERExecutionVisible) AggressorSide() Side {
+	return lv.Direction.Opposite()
+}
+
+// Type returns ExecutionVisible, implementing LOBSTERMessage.
+func (lv *LOBSTERExecutionVisible) Type() Event {
+	return ExecutionVisible
+}
+
+// Time returns EventSinceMidnight, implementing LOBSTERMessage.
+func (lv *LOBSTERExecutionVisible) Time() time.Duration {
+	return lv.EventSinceMidnight
+}
+
+// GetOrderID implements LOBSTERMessage.
+func (lv *LOBSTERExecutionVisible) GetOrderID() uint64 {
+	return lv.OrderID
+}
+
+// GetSize implements LOBSTERMessage.
+func (lv *LOBSTERExecutionVisible) GetSize() uint64 {
+	return lv.Size
+}
+
+// GetPrice implements LOBSTERMessage.
+func (lv *LOBSTERExecutionVisible) GetPrice() Price {
+	return lv.Price
+}
+
+// Side implements LOBSTERMessage.
+func (lv *LOBSTERExecutionVisible) Side() Side {
+	return lv.Direction
+}
@@ -0,0 +1,127 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileKind represents which of the two files of a LOBSTER dataset a
+// file is.
+type FileKind string
+
+const (
+	MessageFile   FileKind = "message"
+	OrderBookFile FileKind = "orderbook"
+)
+
+// filenameDateLayout is the layout of the trading date in LOBSTER
+// filenames.
+const filenameDateLayout = "2006-01-02"
+
+// DatasetInfo is the metadata encoded in the name of a LOBSTER file,
+// for example AAPL_2012-06-21_34200000_57600000_message_10.csv.
+type DatasetInfo struct {
+	Ticker string `json:"ticker"`
+	// Date is the trading date, at midnight UTC.
+	Date time.Time `json:"date"`
+	// Start and End are the bounds of the requested time window, as
+	// times since midnight.
+	Start  time.Duration `json:"start"`
+	End    time.Duration `json:"end"`
+	Kind   FileKind      `json:"kind"`
+	Levels int           `json:"levels"`
+}
+
+// ParseFilename parses the name of a LOBSTER message or orderbook
+// file into a DatasetInfo. Any directories in the path are ignored.
+func ParseFilename(path string) (info DatasetInfo, err error) {
+	name := filepath.Base(path)
+	if !strings.HasSuffix(name, ".csv") {
+		err = fmt.Errorf("Error parsing LOBSTER filename %q, it does not end in .csv", name)
+		return
+	}
+
+	// Tickers may contain underscores themselves, so the fields are
+	// taken from the end of the name
+	parts := strings.Split(strings.TrimSuffix(name, ".csv"), "_")
+	if len(parts) < 6 {
+		err = fmt.Errorf("Error parsing LOBSTER filename %q, it does not have 6 underscore separated fields", name)
+		return
+	}
+	fields := parts[len(parts)-5:]
+	info.Ticker = strings.Join(parts[:len(parts)-5], "_")
+
+	if info.Date, err = time.Parse(filenameDateLayout, fields[0]); err != nil {
+		err = fmt.Errorf("Error parsing date in LOBSTER filename %q: %s", name, err)
+		return
+	}
+
+	var startMillis uint64
+	if startMillis, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+		err = fmt.Errorf("Error parsing start time in LOBSTER filename %q as uint64: %s", name, err)
+		return
+	}
+	info.Start = time.Duration(startMillis) * time.Millisecond
+
+	var endMillis uint64
+	if endMillis, err = strconv.ParseUint(fields[2], 10, 64); err != nil {
+		err = fmt.Errorf("Error parsing end time in LOBSTER filename %q as uint64: %s", name, err)
+		return
+	}
+	info.End = time.Duration(endMillis) * time.Millisecond
+
+	info.Kind = FileKind(fields[3])
+	if info.Kind != MessageFile && info.Kind != OrderBookFile {
+		err = fmt.Errorf("Error parsing LOBSTER filename %q, %q is neither %q nor %q", name, fields[3], MessageFile, OrderBookFile)
+		return
+	}
+
+	if info.Levels, err = strconv.Atoi(fields[4]); err != nil {
+		err = fmt.Errorf("Error parsing number of levels in LOBSTER filename %q as int: %s", name, err)
+		return
+	}
+	if info.Levels <= 0 {
+		err = fmt.Errorf("Error parsing LOBSTER filename %q, number of levels must be positive", name)
+		return
+	}
+	return
+}
+
+// Filename returns the LOBSTER filename described by the DatasetInfo.
+func (di DatasetInfo) Filename() string {
+	return fmt.Sprintf("%s_%s_%d_%d_%s_%d.csv",
+		di.Ticker,
+		di.Date.Format(filenameDateLayout),
+		di.Start/time.Millisecond,
+		di.End/time.Millisecond,
+		di.Kind,
+		di.Levels,
+	)
+}
+
+// MessageFilename returns the name of the message file of the dataset.
+func (di DatasetInfo) MessageFilename() string {
+	di.Kind = MessageFile
+	return di.Filename()
+}
+
+// OrderBookFilename returns the name of the orderbook file of the
+// dataset.
+func (di DatasetInfo) OrderBookFilename() string {
+	di.Kind = OrderBookFile
+	return di.Filename()
+}
+
+// OrderBookPath returns the path of the orderbook file that sits next
+// to the given message file.
+func OrderBookPath(messagePath string) (orderbookPath string, err error) {
+	var info DatasetInfo
+	if info, err = ParseFilename(messagePath); err != nil {
+		return
+	}
+	orderbookPath = filepath.Join(filepath.Dir(messagePath), info.OrderBookFilename())
+	return
+}
@@ -0,0 +1,122 @@
+package lobsterdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// UnmarshalJSONMessage unmarshals a single JSON document written by the
+// MarshalJSON method of a LOBSTER message into the LOBSTERData type
+// matching its eventtype field.
+func UnmarshalJSONMessage(jsonBytes []byte) (data LOBSTERData, err error) {
+	var header struct {
+		EventType Event `json:"eventtype"`
+	}
+	if err = json.Unmarshal(jsonBytes, &header); err != nil {
+		err = fmt.Errorf("Error unmarshalling LOBSTER JSON: %s", err)
+		return
+	}
+
+	if data = newMessage(header.EventType); data == nil {
+		err = fmt.Errorf("Error unmarshalling LOBSTER JSON with event type %q: %w", header.EventType, ErrUnknownEvent)
+		return
+	}
+
+	if err = json.Unmarshal(jsonBytes, data); err != nil {
+		data = nil
+		return
+	}
+	return
+}
+
+// DecodeEventList decodes a document of the form {"events": [...]},
+// as written by lobsterjson, into its LOBSTER messages.
+func DecodeEventList(r io.Reader) (events []LOBSTERData, err error) {
+	var document struct {
+		Events []json.RawMessage `json:"events"`
+	}
+	if err = json.NewDecoder(r).Decode(&document); err != nil {
+		err = fmt.Errorf("Error decoding LOBSTER event list JSON: %s", err)
+		return
+	}
+
+	events = make([]LOBSTERData, len(document.Events))
+	for i, eventJSON := range document.Events {
+		if events[i], err = UnmarshalJSONMessage(eventJSON); err != nil {
+			events = nil
+			err = fmt.Errorf("Error decoding event %d of LOBSTER event list: %w", i+1, err)
+			return
+		}
+	}
+	return
+}
+
+// JSONDecoder reads a stream of JSON documents written by the
+// MarshalJSON methods of LOBSTER messages, such as newline delimited
+// JSON, decoding each into the LOBSTERData type matching its eventtype
+// field.
+type JSONDecoder struct {
+	decoder *json.Decoder
+	count   uint64
+	current LOBSTERData
+	err     error
+}
+
+// NewJSONDecoder returns a JSONDecoder that reads from r.
+func NewJSONDecoder(r io.Reader) *JSONDecoder {
+	return &JSONDecoder{
+		decoder: json.NewDecoder(r),
+	}
+}
+
+// Read decodes the next message in the stream. It returns io.EOF once
+// there are no messages left. A message with an unknown or mismatched
+// event type does not prevent the following messages from being read,
+// but invalid JSON does.
+func (jd *JSONDecoder) Read() (data LOBSTERData, err error) {
+	var messageJSON json.RawMessage
+	if err = jd.decoder.Decode(&messageJSON); err != nil {
+		if err != io.EOF {
+			err = fmt.Errorf("Error decoding LOBSTER JSON after message %d: %w", jd.count, err)
+		}
+		return
+	}
+	jd.count++
+
+	if data, err = UnmarshalJSONMessage(messageJSON); err != nil {
+		err = fmt.Errorf("Error decoding LOBSTER JSON message %d: %w", jd.count, err)
+		return
+	}
+	return
+}
+
+// Next advances the decoder to the next message, which is then
+// available through Data. It returns false when the end of the stream
+// is reached or an error occurs, after which Err reports the error.
+func (jd *JSONDecoder) Next() bool {
+	if jd.err != nil {
+		return false
+	}
+	jd.current, jd.err = jd.Read()
+	return jd.err == nil
+}
+
+// Data returns the most recent message read by Next.
+func (jd *JSONDecoder) Data() LOBSTERData {
+	return jd.current
+}
+
+// Err returns the first error encountered by Next, or nil if Next
+// stopped because the end of the stream was reached.
+func (jd *JSONDecoder) Err() error {
+	if jd.err == io.EOF {
+		return nil
+	}
+	return jd.err
+}
+
+// Count returns the number of messages read so far.
+func (jd *JSONDecoder) Count() uint64 {
+	return jd.count
+}
@@ -0,0 +1,149 @@
+package lobsterdata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TimeFormat represents how MarshalJSONWithOptions writes the time of
+// each message.
+type TimeFormat string
+
+const (
+	// TimeSinceMidnight only writes the time since midnight in
+	// nanoseconds, like MarshalJSON does.
+	TimeSinceMidnight TimeFormat = ""
+	// TimeRFC3339Nano adds the absolute time as an RFC 3339 string with
+	// nanoseconds.
+	TimeRFC3339Nano TimeFormat = "rfc3339nano"
+	// TimeEpochNanos adds the absolute time as nanoseconds since the
+	// Unix epoch.
+	TimeEpochNanos TimeFormat = "epochnanos"
+)
+
+// JSONOptions changes how MarshalJSONWithOptions writes LOBSTER
+// messages. The zero value writes them exactly like their MarshalJSON
+// methods.
+type JSONOptions struct {
+	// DollarPrices writes prices as decimal dollar strings such as
+	// "585.3300" instead of integers.
+	DollarPrices bool
+	// TimeFormat adds a "timestamp" field with the absolute time of the
+	// message after its time since midnight, which requires TradingDay
+	// to be set.
+	TimeFormat TimeFormat
+	TradingDay TradingDay
+}
+
+// jsonField is a single field of a JSON object.
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+// MarshalJSONWithOptions marshals a LOBSTER message into the same JSON
+// document as its MarshalJSON method, with the fields of the event
+// rewritten according to the options.
+func MarshalJSONWithOptions(data LOBSTERData, options JSONOptions) (jsonBytes []byte, err error) {
+	if jsonBytes, err = json.Marshal(data); err != nil || options == (JSONOptions{}) {
+		return
+	}
+
+	var messageTime time.Time
+	if options.TimeFormat != TimeSinceMidnight {
+		if options.TimeFormat != TimeRFC3339Nano && options.TimeFormat != TimeEpochNanos {
+			err = fmt.Errorf("Error marshalling LOBSTER JSON, unknown time format %q", options.TimeFormat)
+			return
+		}
+		if options.TradingDay.IsZero() {
+			err = fmt.Errorf("Error marshalling LOBSTER JSON, time format %q requires a trading day", options.TimeFormat)
+			return
+		}
+		messageTime, _ = options.TradingDay.MessageTime(data)
+	}
+
+	var document struct {
+		TheMainEvent json.RawMessage `json:"event"`
+		EventType    Event           `json:"eventtype"`
+	}
+	if err = json.Unmarshal(jsonBytes, &document); err != nil {
+		err = fmt.Errorf("Error unmarshalling LOBSTER JSON to rewrite it: %s", err)
+		return
+	}
+
+	if document.TheMainEvent, err = rewriteJSONFields(document.TheMainEvent, func(key string, value json.RawMessage) (fields []jsonField, err error) {
+		fields = []jsonField{{key, value}}
+		switch {
+		case key == "price" && options.DollarPrices:
+			var price Price
+			if err = json.Unmarshal(value, &price); err != nil {
+				return
+			}
+			fields[0].value, err = json.Marshal(price.String())
+		case key == "timesincemidnight" && options.TimeFormat == TimeRFC3339Nano:
+			var timestamp json.RawMessage
+			timestamp, err = json.Marshal(messageTime.Format(time.RFC3339Nano))
+			fields = append(fields, jsonField{"timestamp", timestamp})
+		case key == "timesincemidnight" && options.TimeFormat == TimeEpochNanos:
+			var timestamp json.RawMessage
+			timestamp, err = json.Marshal(messageTime.UnixNano())
+			fields = append(fields, jsonField{"timestamp", timestamp})
+		}
+		return
+	}); err != nil {
+		return
+	}
+	return json.Marshal(document)
+}
+
+// rewriteJSONFields calls rewrite on every field of a flat JSON object,
+// replacing each field with the fields returned and keeping the fields
+// in order.
+func rewriteJSONFields(object json.RawMessage, rewrite func(key string, value json.RawMessage) ([]jsonField, error)) (rewritten json.RawMessage, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(object))
+	var token json.Token
+	if token, err = decoder.Token(); err != nil {
+		return
+	}
+	if token != json.Delim('{') {
+		err = fmt.Errorf("Error rewriting LOBSTER JSON, expected an object but found %v", token)
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for decoder.More() {
+		if token, err = decoder.Token(); err != nil {
+			return
+		}
+		key, _ := token.(string)
+
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return
+		}
+		var fields []jsonField
+		if fields, err = rewrite(key, value); err != nil {
+			err = fmt.Errorf("Error rewriting %s field of LOBSTER JSON: %s", key, err)
+			return
+		}
+
+		for _, field := range fields {
+			var keyBytes []byte
+			if keyBytes, err = json.Marshal(field.key); err != nil {
+				return
+			}
+			if buf.Len() > 1 {
+				buf.WriteByte(',')
+			}
+			buf.Write(keyBytes)
+			buf.WriteByte(':')
+			buf.Write(field.value)
+		}
+	}
+	buf.WriteByte('}')
+	rewritten = buf.Bytes()
+	return
+}
@@ -1,5 +1,7 @@
 package lobsterdata
 
+import "time"
+
 // LOBSTERData is an interface for all types of LOBSTER Data, it
 // specifies that LOBSTER Data should marshal and unmarshal from an
 // output of a csv
@@ -14,3 +16,33 @@ type LOBSTERData interface {
 	// by encoding/csv.
 	MarshalCsvLOBSTER() ([]string, error)
 }
+
+// LOBSTERMessage is an interface implemented by all seven LOBSTER
+// message types, giving access to the fields they have in common.
+// OrderID, Size and Price are already the names of fields of the
+// message structs, so their accessors are prefixed with Get.
+type LOBSTERMessage interface {
+	LOBSTERData
+
+	// Type returns the event type of the message.
+	Type() Event
+
+	// Time returns the time of the message since midnight.
+	Time() time.Duration
+
+	// GetOrderID returns the orderid of the message, which is 0 for
+	// hidden executions and trading halts.
+	GetOrderID() uint64
+
+	// GetSize returns the size of the message, which is 0 for trading
+	// halts.
+	GetSize() uint64
+
+	// GetPrice returns the price of the message, which is 0 for
+	// trading halts.
+	GetPrice() Price
+
+	// Side returns the direction of the message, which is 0 for
+	// trading halts.
+	Side() Side
+}
@@ -0,0 +1,143 @@
+package lobsterdata
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// newMessage returns an empty LOBSTERMessage of the type corresponding to
+// the given event, or nil if the event is not known.
+func newMessage(event Event) LOBSTERMessage {
+	switch event {
+	case Submission:
+		return new(LOBSTERSubmission)
+	case Cancellation:
+		return new(LOBSTERCancellation)
+	case Deletion:
+		return new(LOBSTERDeletion)
+	case ExecutionVisible:
+		return new(LOBSTERExecutionVisible)
+	case ExecutionHidden:
+		return new(LOBSTERExecutionHidden)
+	case CrossTrade:
+		return new(LOBSTERCrossTrade)
+	case TradingHalt:
+		return new(LOBSTERTradingHalt)
+	}
+	return nil
+}
+
+// UnmarshalCsvMessage unmarshals a list of strings parsed from a
+// LOBSTER message file into the LOBSTERData type matching the event
+// type in the second column.
+func UnmarshalCsvMessage(eventFields []string) (data LOBSTERData, err error) {
+	if len(eventFields) != 6 {
+		err = columnCountError("", len(eventFields), 6)
+		return
+	}
+
+	if data = newMessage(Event(eventFields[1])); data == nil {
+		err = messageFieldError("", eventFields, 1, ErrUnknownEvent)
+		return
+	}
+
+	if err = data.UnmarshalCsvLOBSTER(eventFields); err != nil {
+		data = nil
+		return
+	}
+	return
+}
+
+// MessageReader reads rows from a LOBSTER message file, decoding each
+// row into the LOBSTERData type that matches its event type.
+type MessageReader struct {
+	csvReader     *csv.Reader
+	line          uint64
+	current       LOBSTERData
+	err           error
+	sinceMidnight time.Duration
+	tradingDay    TradingDay
+}
+
+// NewMessageReader returns a MessageReader that reads LOBSTER message
+// rows from r.
+func NewMessageReader(r io.Reader) *MessageReader {
+	csvReader := csv.NewReader(r)
+	// The number of columns is checked when unmarshalling each row
+	csvReader.FieldsPerRecord = -1
+	// Rows are parsed into structs straight away, so the record slice
+	// can be reused between rows
+	csvReader.ReuseRecord = true
+	return &MessageReader{
+		csvReader: csvReader,
+	}
+}
+
+// Read reads and decodes the next row of the message file. It returns
+// io.EOF once there are no rows left. An error decoding a single row
+// does not prevent the following rows from being read.
+func (mr *MessageReader) Read() (data LOBSTERData, err error) {
+	var csvLine []string
+	if csvLine, err = mr.csvReader.Read(); err == io.EOF {
+		return
+	}
+	mr.line++
+	if err != nil {
+		err = withLine(err, mr.line)
+		return
+	}
+
+	if data, err = UnmarshalCsvMessage(csvLine); err != nil {
+		err = withLine(err, mr.line)
+		return
+	}
+	mr.sinceMidnight, _ = eventSinceMidnight(data)
+	return
+}
+
+// Next advances the reader to the next message, which is then
+// available through Data. It returns false when the end of the file is
+// reached or an error occurs, after which Err reports the error.
+func (mr *MessageReader) Next() bool {
+	if mr.err != nil {
+		return false
+	}
+	mr.current, mr.err = mr.Read()
+	return mr.err == nil
+}
+
+// Data returns the most recent message read by Next.
+func (mr *MessageReader) Data() LOBSTERData {
+	return mr.current
+}
+
+// Err returns the first error encountered by Next, or nil if Next
+// stopped because the end of the file was reached.
+func (mr *MessageReader) Err() error {
+	if mr.err == io.EOF {
+		return nil
+	}
+	return mr.err
+}
+
+// Line returns the line number of the most recently read row, starting
+// at 1 for the first row of the file.
+func (mr *MessageReader) Line() uint64 {
+	return mr.line
+}
+
+// SetTradingDay sets the trading day the file was recorded on, which
+// Time uses to give the absolute time of each message.
+func (mr *MessageReader) SetTradingDay(tradingDay TradingDay) {
+	mr.tradingDay = tradingDay
+}
+
+// Time returns the absolute time of the most recently read message, or
+// the zero time if no trading day has been set.
+func (mr *MessageReader) Time() time.Time {
+	if mr.tradingDay.IsZero() {
+		return time.Time{}
+	}
+	return mr.tradingDay.Time(mr.sinceMidnight)
+}
@@ -0,0 +1,91 @@
+package lobsterdata
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrOutOfOrder is returned when writing a message whose timestamp is
+// earlier than that of the message written before it.
+var ErrOutOfOrder = errors.New("LOBSTER message timestamps must not decrease")
+
+// eventSinceMidnight returns the timestamp of a LOBSTER message. The
+// second return value is false if data is not one of the message
+// types.
+func eventSinceMidnight(data LOBSTERData) (sinceMidnight time.Duration, ok bool) {
+	var message LOBSTERMessage
+	if message, ok = data.(LOBSTERMessage); ok {
+		sinceMidnight = message.Time()
+	}
+	return
+}
+
+// MessageWriter writes a stream of LOBSTER messages of any type to a
+// LOBSTER message file, checking that their timestamps never
+// decrease.
+type MessageWriter struct {
+	// TimestampPrecision is the number of digits written after the
+	// decimal point of each timestamp. The default of -1 writes
+	// timestamps exactly, see FormatTimestamp.
+	TimestampPrecision int
+
+	csvWriter *csv.Writer
+	last      time.Duration
+	count     uint64
+}
+
+// NewMessageWriter returns a MessageWriter that writes LOBSTER message
+// rows to w.
+func NewMessageWriter(w io.Writer) *MessageWriter {
+	return &MessageWriter{
+		TimestampPrecision: -1,
+		csvWriter:          csv.NewWriter(w),
+	}
+}
+
+// Write writes a single message as a row of the message file. Rows
+// are buffered, so Flush must be called once all messages have been
+// written.
+func (mw *MessageWriter) Write(data LOBSTERData) (err error) {
+	sinceMidnight, ok := eventSinceMidnight(data)
+	if !ok {
+		err = fmt.Errorf("Error writing LOBSTER message, %T is not a LOBSTER message type", data)
+		return
+	}
+
+	if mw.count > 0 && sinceMidnight < mw.last {
+		err = fmt.Errorf("Error writing LOBSTER message %d, timestamp %s is before %s: %w", mw.count+1, sinceMidnight, mw.last, ErrOutOfOrder)
+		return
+	}
+
+	var eventFields []string
+	if eventFields, err = data.MarshalCsvLOBSTER(); err != nil {
+		err = fmt.Errorf("Error marshalling LOBSTER message %d: %w", mw.count+1, err)
+		return
+	}
+	eventFields[0] = FormatTimestamp(sinceMidnight, mw.TimestampPrecision)
+
+	if err = mw.csvWriter.Write(eventFields); err != nil {
+		err = fmt.Errorf("Error writing LOBSTER message %d: %w", mw.count+1, err)
+		return
+	}
+
+	mw.last = sinceMidnight
+	mw.count++
+	return
+}
+
+// Flush writes any buffered rows to the underlying io.Writer, and
+// returns any error that occurred while writing.
+func (mw *MessageWriter) Flush() error {
+	mw.csvWriter.Flush()
+	return mw.csvWriter.Error()
+}
+
+// Count returns the number of messages written so far.
+func (mw *MessageWriter) Count() uint64 {
+	return mw.count
+}
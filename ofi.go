@@ -0,0 +1,194 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// OFIOptions configures how an OFIAggregator windows order flow
+// imbalance. Exactly one of Interval and Events must be set.
+type OFIOptions struct {
+	// Levels is the number of price levels of each side the order flow
+	// and queue imbalances are computed for, 1 if not set.
+	Levels int
+	// Interval is the length of clock windows, which cover multiples
+	// of Interval since midnight.
+	Interval time.Duration
+	// Events is the number of messages in each event window.
+	Events int
+}
+
+// OFIWindow is the order flow imbalance of every level over a window
+// of messages.
+type OFIWindow struct {
+	// Start and End are the interval covered by the window for clock
+	// windows, or the times of its first and last messages for event
+	// windows.
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	// Events is the number of messages in the window.
+	Events int `json:"events"`
+	// OFI is the order flow imbalance at each level, where index 0 is
+	// the best level. Positive values mean buying pressure.
+	OFI []int64 `json:"ofi"`
+	// QueueImbalance is the imbalance between the bid and ask sizes at
+	// each level at the end of the window, from -1 when there is only
+	// ask size to 1 when there is only bid size.
+	QueueImbalance []float64 `json:"queueimbalance"`
+}
+
+// OFIAggregator computes the order flow imbalance of Cont, Kukanov and
+// Stoikov from consecutive orderbook snapshots, at the best level and
+// at each deeper level, summed over clock or event windows.
+//
+// Between two snapshots, the flow at a level is the bid size added at
+// that level minus the ask size added, where a bid level that improves
+// counts all of its size as added and one that worsens counts all of
+// its previous size as removed, and likewise for asks. Levels that do
+// not exist count as having no size.
+type OFIAggregator struct {
+	options  OFIOptions
+	previous *OrderBookSnapshot
+	current  *OFIWindow
+}
+
+// NewOFIAggregator returns an OFIAggregator with the given options.
+func NewOFIAggregator(options OFIOptions) (aggregator *OFIAggregator, err error) {
+	if options.Levels <= 0 {
+		options.Levels = 1
+	}
+	if (options.Interval > 0) == (options.Events > 0) {
+		err = fmt.Errorf("Error creating OFI aggregator, exactly one of a positive interval or number of events is needed")
+		return
+	}
+	aggregator = &OFIAggregator{
+		options: options,
+	}
+	return
+}
+
+// Apply adds the orderbook snapshot after a message to the aggregator,
+// returning the window it completes, if any. The first snapshot only
+// sets the state the next one is compared to. The snapshot is kept
+// until the next call, so it must not be modified in between.
+func (oa *OFIAggregator) Apply(sinceMidnight time.Duration, book *OrderBookSnapshot) (completed *OFIWindow) {
+	previous := oa.previous
+	oa.previous = book
+	if previous == nil {
+		return
+	}
+
+	if oa.options.Interval > 0 {
+		start := sinceMidnight - sinceMidnight%oa.options.Interval
+		if oa.current != nil && oa.current.Start != start {
+			completed = oa.current
+			oa.current = nil
+		}
+		if oa.current == nil {
+			oa.current = oa.newWindow(start)
+			oa.current.End = start + oa.options.Interval
+		}
+	} else {
+		if oa.current == nil {
+			oa.current = oa.newWindow(sinceMidnight)
+		}
+		oa.current.End = sinceMidnight
+	}
+
+	window := oa.current
+	window.Events++
+	for i := 0; i < oa.options.Levels; i++ {
+		window.OFI[i] += levelFlow(previous, book, i)
+		window.QueueImbalance[i] = queueImbalance(book, i)
+	}
+
+	if oa.options.Events > 0 && window.Events == oa.options.Events {
+		completed = window
+		oa.current = nil
+	}
+	return
+}
+
+// Flush returns the window currently being built, or nil if it has no
+// messages. It should be called after the last snapshot has been
+// applied.
+func (oa *OFIAggregator) Flush() (window *OFIWindow) {
+	window, oa.current = oa.current, nil
+	return
+}
+
+// newWindow returns an empty window starting at start.
+func (oa *OFIAggregator) newWindow(start time.Duration) *OFIWindow {
+	return &OFIWindow{
+		Start:          start,
+		OFI:            make([]int64, oa.options.Levels),
+		QueueImbalance: make([]float64, oa.options.Levels),
+	}
+}
+
+// snapshotLevel returns a level of a snapshot, with levels that do not
+// exist having no size.
+func snapshotLevel(side func(index int) (PriceLevel, bool), index int, dummy Price) PriceLevel {
+	if level, ok := side(index); ok && !level.Empty() {
+		return level
+	}
+	return PriceLevel{Price: dummy}
+}
+
+// levelFlow returns the order flow at the given level between two
+// snapshots.
+func levelFlow(previous *OrderBookSnapshot, next *OrderBookSnapshot, index int) (flow int64) {
+	previousBid := snapshotLevel(previous.Bid, index, DummyBidPrice)
+	nextBid := snapshotLevel(next.Bid, index, DummyBidPrice)
+	previousAsk := snapshotLevel(previous.Ask, index, DummyAskPrice)
+	nextAsk := snapshotLevel(next.Ask, index, DummyAskPrice)
+
+	if nextBid.Price >= previousBid.Price {
+		flow += int64(nextBid.Size)
+	}
+	if nextBid.Price <= previousBid.Price {
+		flow -= int64(previousBid.Size)
+	}
+	if nextAsk.Price <= previousAsk.Price {
+		flow -= int64(nextAsk.Size)
+	}
+	if nextAsk.Price >= previousAsk.Price {
+		flow += int64(previousAsk.Size)
+	}
+	return
+}
+
+// queueImbalance returns the imbalance between the bid and ask sizes
+// at the given level of a snapshot, or 0 if both are empty.
+func queueImbalance(book *OrderBookSnapshot, index int) float64 {
+	bid := snapshotLevel(book.Bid, index, DummyBidPrice)
+	ask := snapshotLevel(book.Ask, index, DummyAskPrice)
+	if bid.Size+ask.Size == 0 {
+		return 0
+	}
+	return (float64(bid.Size) - float64(ask.Size)) / float64(bid.Size+ask.Size)
+}
+
+// OrderFlowImbalance reads a LOBSTER message file and its orderbook
+// file and returns their order flow imbalance windows.
+func OrderFlowImbalance(messages io.Reader, orderbook io.Reader, levels int, options OFIOptions) (windows []*OFIWindow, err error) {
+	var aggregator *OFIAggregator
+	if aggregator, err = NewOFIAggregator(options); err != nil {
+		return
+	}
+	pairedReader := NewPairedReader(messages, orderbook, levels)
+	for pairedReader.Next() {
+		sinceMidnight, _ := eventSinceMidnight(pairedReader.Message())
+		if window := aggregator.Apply(sinceMidnight, pairedReader.OrderBook()); window != nil {
+			windows = append(windows, window)
+		}
+	}
+	if err = pairedReader.Err(); err != nil {
+		return
+	}
+	if window := aggregator.Flush(); window != nil {
+		windows = append(windows, window)
+	}
+	return
+}
@@ -0,0 +1,153 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// DummyAskPrice is the price LOBSTER uses for ask levels that do
+	// not exist in the orderbook.
+	DummyAskPrice Price = 9999999999
+
+	// DummyBidPrice is the price LOBSTER uses for bid levels that do
+	// not exist in the orderbook.
+	DummyBidPrice Price = -9999999999
+)
+
+// PriceLevel is a single price level of one side of the orderbook.
+type PriceLevel struct {
+	Price Price  `json:"price"`
+	Size  uint64 `json:"size"`
+}
+
+// Empty returns whether the price level is a placeholder for a level
+// that does not exist in the orderbook.
+func (pl PriceLevel) Empty() bool {
+	return pl.Size == 0 && (pl.Price == DummyAskPrice || pl.Price == DummyBidPrice)
+}
+
+// OrderBookSnapshot is a struct that represents a single row of a
+// LOBSTER orderbook file, which is the state of the first Levels
+// price levels of each side of the book after the corresponding
+// message.
+type OrderBookSnapshot struct {
+	// Levels is the number of price levels on each side. If it is 0
+	// when unmarshalling, it is inferred from the number of columns.
+	Levels int          `json:"levels"`
+	Asks   []PriceLevel `json:"asks"`
+	Bids   []PriceLevel `json:"bids"`
+}
+
+// NewOrderBookSnapshot returns an OrderBookSnapshot that expects the
+// given number of price levels per side.
+func NewOrderBookSnapshot(levels int) *OrderBookSnapshot {
+	return &OrderBookSnapshot{
+		Levels: levels,
+	}
+}
+
+// Ask returns the ask price level at the given index, where index 0
+// is the best ask. The second return value is false if the index is
+// out of range.
+func (ob *OrderBookSnapshot) Ask(index int) (level PriceLevel, ok bool) {
+	if index < 0 || index >= len(ob.Asks) {
+		return
+	}
+	return ob.Asks[index], true
+}
+
+// Bid returns the bid price level at the given index, where index 0
+// is the best bid. The second return value is false if the index is
+// out of range.
+func (ob *OrderBookSnapshot) Bid(index int) (level PriceLevel, ok bool) {
+	if index < 0 || index >= len(ob.Bids) {
+		return
+	}
+	return ob.Bids[index], true
+}
+
+// UnmarshalCsvLOBSTER unmarshals a list of strings into an
+// OrderBookSnapshot, given they are parsed from encoding/csv.
+func (ob *OrderBookSnapshot) UnmarshalCsvLOBSTER(bookFields []string) (err error) {
+	if ob.Levels == 0 {
+		if len(bookFields) == 0 || len(bookFields)%4 != 0 {
+			err = columnCountError("", len(bookFields), 0)
+			return
+		}
+		ob.Levels = len(bookFields) / 4
+	} else if len(bookFields) != 4*ob.Levels {
+		err = columnCountError("", len(bookFields), 4*ob.Levels)
+		return
+	}
+
+	// Reuse the level slices where possible, since snapshots are
+	// usually unmarshalled one after another
+	if cap(ob.Asks) < ob.Levels {
+		ob.Asks = make([]PriceLevel, ob.Levels)
+	}
+	ob.Asks = ob.Asks[:ob.Levels]
+	if cap(ob.Bids) < ob.Levels {
+		ob.Bids = make([]PriceLevel, ob.Levels)
+	}
+	ob.Bids = ob.Bids[:ob.Levels]
+
+	// Each level is made up of four columns: ask price, ask size, bid
+	// price and bid size
+	for i := 0; i < ob.Levels; i++ {
+		var askPrice int64
+		if askPrice, err = strconv.ParseInt(bookFields[4*i], 10, 64); err != nil {
+			err = bookFieldError(bookFields, 4*i, err)
+			return
+		}
+		ob.Asks[i].Price = Price(askPrice)
+
+		if ob.Asks[i].Size, err = strconv.ParseUint(bookFields[4*i+1], 10, 64); err != nil {
+			err = bookFieldError(bookFields, 4*i+1, err)
+			return
+		}
+
+		var bidPrice int64
+		if bidPrice, err = strconv.ParseInt(bookFields[4*i+2], 10, 64); err != nil {
+			err = bookFieldError(bookFields, 4*i+2, err)
+			return
+		}
+		ob.Bids[i].Price = Price(bidPrice)
+
+		if ob.Bids[i].Size, err = strconv.ParseUint(bookFields[4*i+3], 10, 64); err != nil {
+			err = bookFieldError(bookFields, 4*i+3, err)
+			return
+		}
+	}
+	return
+}
+
+// bookFieldError returns a ParseError for the field of a LOBSTER
+// orderbook row at the given 0-based index.
+func bookFieldError(bookFields []string, index int, err error) *ParseError {
+	names := [4]string{"askprice", "asksize", "bidprice", "bidsize"}
+	return &ParseError{
+		Column: index + 1,
+		Field:  fmt.Sprintf("%s%d", names[index%4], index/4+1),
+		Value:  bookFields[index],
+		Err:    err,
+	}
+}
+
+// MarshalCsvLOBSTER marshals an OrderBookSnapshot into a set of
+// strings that can be written using encoding/csv.
+func (ob *OrderBookSnapshot) MarshalCsvLOBSTER() (bookFields []string, err error) {
+	if len(ob.Asks) != ob.Levels || len(ob.Bids) != ob.Levels {
+		err = fmt.Errorf("Error marshalling LOBSTER orderbook, expected %d levels per side but have %d asks and %d bids", ob.Levels, len(ob.Asks), len(ob.Bids))
+		return
+	}
+
+	bookFields = make([]string, 4*ob.Levels)
+	for i := 0; i < ob.Levels; i++ {
+		bookFields[4*i] = fmt.Sprintf("%d", ob.Asks[i].Price)
+		bookFields[4*i+1] = fmt.Sprintf("%d", ob.Asks[i].Size)
+		bookFields[4*i+2] = fmt.Sprintf("%d", ob.Bids[i].Price)
+		bookFields[4*i+3] = fmt.Sprintf("%d", ob.Bids[i].Size)
+	}
+	return
+}
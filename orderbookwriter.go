@@ -0,0 +1,87 @@
+package lobsterdata
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// OrderBookWriter writes orderbook snapshots to a LOBSTER orderbook
+// file, checking that every row has the same number of levels.
+type OrderBookWriter struct {
+	// TimeColumn adds the time of each sample written with WriteSample
+	// as a first column, formatted like the timestamps of a message
+	// file. Rows written with Write never have a time column.
+	TimeColumn bool
+
+	csvWriter *csv.Writer
+	levels    int
+	count     uint64
+}
+
+// NewOrderBookWriter returns an OrderBookWriter that writes LOBSTER
+// orderbook rows to w.
+func NewOrderBookWriter(w io.Writer) *OrderBookWriter {
+	return &OrderBookWriter{
+		csvWriter: csv.NewWriter(w),
+	}
+}
+
+// Write writes a single orderbook snapshot.
+func (obw *OrderBookWriter) Write(book *OrderBookSnapshot) (err error) {
+	var bookFields []string
+	if bookFields, err = obw.marshal(book); err != nil {
+		return
+	}
+	return obw.write(bookFields)
+}
+
+// WriteSample writes the orderbook snapshot of a Sample, with its time
+// if TimeColumn is set.
+func (obw *OrderBookWriter) WriteSample(sample Sample) (err error) {
+	var bookFields []string
+	if bookFields, err = obw.marshal(sample.OrderBook); err != nil {
+		return
+	}
+	if obw.TimeColumn {
+		bookFields = append([]string{FormatTimestamp(sample.Time, -1)}, bookFields...)
+	}
+	return obw.write(bookFields)
+}
+
+// marshal marshals a snapshot, checking its number of levels against
+// the first snapshot written.
+func (obw *OrderBookWriter) marshal(book *OrderBookSnapshot) (bookFields []string, err error) {
+	if obw.count > 0 && book.Levels != obw.levels {
+		err = fmt.Errorf("Error writing LOBSTER orderbook row %d, it has %d levels but the first row has %d", obw.count+1, book.Levels, obw.levels)
+		return
+	}
+	if bookFields, err = book.MarshalCsvLOBSTER(); err != nil {
+		err = fmt.Errorf("Error marshalling LOBSTER orderbook row %d: %w", obw.count+1, err)
+		return
+	}
+	obw.levels = book.Levels
+	return
+}
+
+// write writes a marshalled row.
+func (obw *OrderBookWriter) write(bookFields []string) (err error) {
+	if err = obw.csvWriter.Write(bookFields); err != nil {
+		err = fmt.Errorf("Error writing LOBSTER orderbook row %d: %w", obw.count+1, err)
+		return
+	}
+	obw.count++
+	return
+}
+
+// Flush writes any buffered rows to the underlying io.Writer, and
+// returns any error that occurred while writing.
+func (obw *OrderBookWriter) Flush() error {
+	obw.csvWriter.Flush()
+	return obw.csvWriter.Error()
+}
+
+// Count returns the number of rows written so far.
+func (obw *OrderBookWriter) Count() uint64 {
+	return obw.count
+}
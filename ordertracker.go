@@ -0,0 +1,234 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// OrderState represents how far along its lifetime an order is.
+type OrderState string
+
+const (
+	// OrderActive means the order still has remaining size in the
+	// book.
+	OrderActive OrderState = "active"
+	// OrderFilled means the order was fully executed.
+	OrderFilled OrderState = "filled"
+	// OrderDeleted means the remaining size of the order was deleted,
+	// after it may have been partially executed or cancelled.
+	OrderDeleted OrderState = "deleted"
+)
+
+// OrderEvent is a single execution or cancellation of part of an
+// order.
+type OrderEvent struct {
+	Time time.Duration `json:"time"`
+	Size uint64        `json:"size"`
+}
+
+// OrderHistory is the lifetime of a single order, from its submission
+// until it is filled or deleted.
+//
+// Orders submitted before the start of the data are PreExisting, their
+// Submitted time and Size are unknown and left as 0. Since their
+// remaining size is unknown, they are never known to be filled and
+// are only completed once they are deleted.
+type OrderHistory struct {
+	OrderID     uint64        `json:"orderid"`
+	Price       Price         `json:"price"`
+	Direction   Side          `json:"side"`
+	PreExisting bool          `json:"preexisting"`
+	Submitted   time.Duration `json:"submitted"`
+	// Size is the size the order was submitted with.
+	Size uint64 `json:"size"`
+	// Fills are the visible executions of the order, in order.
+	Fills []OrderEvent `json:"fills"`
+	// Cancels are the partial cancellations of the order followed by
+	// its deletion, if it was deleted.
+	Cancels       []OrderEvent `json:"cancels"`
+	FilledSize    uint64       `json:"filledsize"`
+	CancelledSize uint64       `json:"cancelledsize"`
+	State         OrderState   `json:"state"`
+	// Closed is the time the order was filled or deleted, which is 0
+	// while it is active.
+	Closed time.Duration `json:"closed"`
+}
+
+// Remaining returns the size of the order still resting in the book.
+// It is 0 for PreExisting orders, since their size is unknown.
+func (oh *OrderHistory) Remaining() uint64 {
+	if oh.PreExisting || oh.State != OrderActive {
+		return 0
+	}
+	return oh.Size - oh.FilledSize - oh.CancelledSize
+}
+
+// FillRatio returns the fraction of the order's size that was executed,
+// counting the size that was cancelled or deleted but not the size
+// still resting. It returns 0 if none of the order has been executed
+// or cancelled.
+func (oh *OrderHistory) FillRatio() float64 {
+	total := oh.FilledSize + oh.CancelledSize
+	if total == 0 {
+		return 0
+	}
+	return float64(oh.FilledSize) / float64(total)
+}
+
+// Lifetime returns the time between the submission of the order and
+// it being filled or deleted. The second return value is false if the
+// order is still active or was PreExisting.
+func (oh *OrderHistory) Lifetime() (lifetime time.Duration, ok bool) {
+	if oh.PreExisting || oh.State == OrderActive {
+		return
+	}
+	return oh.Closed - oh.Submitted, true
+}
+
+// OrderTracker follows every order through the LOBSTER messages
+// applied to it, building an OrderHistory for each OrderID. Hidden
+// executions, cross trades and trading halts do not refer to resting
+// orders and are ignored.
+type OrderTracker struct {
+	active map[uint64]*OrderHistory
+}
+
+// NewOrderTracker returns an OrderTracker that is not tracking any
+// orders.
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{
+		active: make(map[uint64]*OrderHistory),
+	}
+}
+
+// Apply updates the order a LOBSTER message refers to. If the message
+// fills or deletes the order, its completed history is returned and it
+// is no longer tracked, otherwise the returned history is nil.
+func (ot *OrderTracker) Apply(data LOBSTERData) (completed *OrderHistory, err error) {
+	message, ok := data.(LOBSTERMessage)
+	if !ok {
+		err = fmt.Errorf("Error tracking LOBSTER order, %T is not a LOBSTER message type", data)
+		return
+	}
+
+	switch message.Type() {
+	case Submission:
+		if _, ok := ot.active[message.GetOrderID()]; ok {
+			err = fmt.Errorf("Error tracking LOBSTER submission, order %d is already active", message.GetOrderID())
+			return
+		}
+		ot.active[message.GetOrderID()] = &OrderHistory{
+			OrderID:   message.GetOrderID(),
+			Price:     message.GetPrice(),
+			Direction: message.Side(),
+			Submitted: message.Time(),
+			Size:      message.GetSize(),
+			State:     OrderActive,
+		}
+		return
+	case Cancellation, Deletion, ExecutionVisible:
+	default:
+		return
+	}
+
+	history, ok := ot.active[message.GetOrderID()]
+	if !ok {
+		history = &OrderHistory{
+			OrderID:     message.GetOrderID(),
+			Price:       message.GetPrice(),
+			Direction:   message.Side(),
+			PreExisting: true,
+			State:       OrderActive,
+		}
+		ot.active[message.GetOrderID()] = history
+	}
+	if history.Price != message.GetPrice() || history.Direction != message.Side() {
+		err = fmt.Errorf("Error tracking LOBSTER order %d, it rests at price %d side %s, not price %d side %s", history.OrderID, history.Price, history.Direction, message.GetPrice(), message.Side())
+		return
+	}
+	if remaining := history.Remaining(); !history.PreExisting && message.GetSize() > remaining {
+		err = fmt.Errorf("Error tracking LOBSTER order %d, cannot remove %d from remaining size %d", history.OrderID, message.GetSize(), remaining)
+		return
+	}
+
+	event := OrderEvent{Time: message.Time(), Size: message.GetSize()}
+	switch message.Type() {
+	case ExecutionVisible:
+		history.Fills = append(history.Fills, event)
+		history.FilledSize += event.Size
+		if !history.PreExisting && history.Remaining() == 0 {
+			history.State = OrderFilled
+		}
+	case Cancellation:
+		history.Cancels = append(history.Cancels, event)
+		history.CancelledSize += event.Size
+	case Deletion:
+		history.Cancels = append(history.Cancels, event)
+		history.CancelledSize += event.Size
+		history.State = OrderDeleted
+	}
+
+	if history.State != OrderActive {
+		history.Closed = message.Time()
+		delete(ot.active, history.OrderID)
+		completed = history
+	}
+	return
+}
+
+// Order returns the history so far of an active order. The second
+// return value is false if the order is not active.
+func (ot *OrderTracker) Order(orderID uint64) (history OrderHistory, ok bool) {
+	var active *OrderHistory
+	if active, ok = ot.active[orderID]; ok {
+		history = *active
+	}
+	return
+}
+
+// NumActive returns the number of active orders being tracked.
+func (ot *OrderTracker) NumActive() int {
+	return len(ot.active)
+}
+
+// Active returns the histories of every active order, ordered by
+// submission time and then OrderID.
+func (ot *OrderTracker) Active() (histories []*OrderHistory) {
+	histories = make([]*OrderHistory, 0, len(ot.active))
+	for _, history := range ot.active {
+		histories = append(histories, history)
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		if histories[i].Submitted != histories[j].Submitted {
+			return histories[i].Submitted < histories[j].Submitted
+		}
+		return histories[i].OrderID < histories[j].OrderID
+	})
+	return
+}
+
+// TrackOrders reads every message of a LOBSTER message file and
+// returns the history of every order in it. Completed orders come
+// first, in the order they were filled or deleted, followed by the
+// orders still active at the end of the file.
+func TrackOrders(messages io.Reader) (histories []*OrderHistory, err error) {
+	messageReader := NewMessageReader(messages)
+	tracker := NewOrderTracker()
+	for messageReader.Next() {
+		var completed *OrderHistory
+		if completed, err = tracker.Apply(messageReader.Data()); err != nil {
+			err = fmt.Errorf("Error tracking LOBSTER orders on line %d: %w", messageReader.Line(), err)
+			return
+		}
+		if completed != nil {
+			histories = append(histories, completed)
+		}
+	}
+	if err = messageReader.Err(); err != nil {
+		return
+	}
+	histories = append(histories, tracker.Active()...)
+	return
+}
@@ -0,0 +1,131 @@
+package lobsterdata
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrUnevenFiles is returned when one of a message file and its
+// orderbook file ends before the other.
+var ErrUnevenFiles = errors.New("LOBSTER message and orderbook files have a different number of rows")
+
+// PairedReader reads a LOBSTER message file and its orderbook file in
+// lockstep, so that each message is returned along with the state of
+// the orderbook directly after it.
+type PairedReader struct {
+	messageReader   *MessageReader
+	orderbookReader *csv.Reader
+	levels          int
+	currentMessage  LOBSTERData
+	currentBook     *OrderBookSnapshot
+	err             error
+}
+
+// NewPairedReader returns a PairedReader that reads messages from
+// messages and orderbook snapshots from orderbook. If levels is 0, the
+// number of levels is inferred from the first orderbook row.
+func NewPairedReader(messages io.Reader, orderbook io.Reader, levels int) *PairedReader {
+	orderbookReader := csv.NewReader(orderbook)
+	// The number of columns is checked when unmarshalling each row
+	orderbookReader.FieldsPerRecord = -1
+	// Rows are parsed into structs straight away, so the record slice
+	// can be reused between rows
+	orderbookReader.ReuseRecord = true
+	return &PairedReader{
+		messageReader:   NewMessageReader(messages),
+		orderbookReader: orderbookReader,
+		levels:          levels,
+	}
+}
+
+// Read reads the next message and the orderbook snapshot that
+// corresponds to it. It returns io.EOF once both files have been read
+// completely, and an error wrapping ErrUnevenFiles if only one of them
+// has.
+func (pr *PairedReader) Read() (message LOBSTERData, book *OrderBookSnapshot, err error) {
+	message, err = pr.messageReader.Read()
+	bookLine, bookErr := pr.orderbookReader.Read()
+
+	if err == io.EOF && bookErr == io.EOF {
+		message = nil
+		return
+	} else if err == io.EOF {
+		message = nil
+		err = fmt.Errorf("Error reading LOBSTER files, message file ended before orderbook file at line %d: %w", pr.messageReader.Line()+1, ErrUnevenFiles)
+		return
+	} else if bookErr == io.EOF {
+		message = nil
+		err = fmt.Errorf("Error reading LOBSTER files, orderbook file ended before message file at line %d: %w", pr.messageReader.Line(), ErrUnevenFiles)
+		return
+	} else if err != nil {
+		message = nil
+		return
+	} else if bookErr != nil {
+		message = nil
+		err = withLine(bookErr, pr.messageReader.Line())
+		return
+	}
+
+	book = NewOrderBookSnapshot(pr.levels)
+	if err = book.UnmarshalCsvLOBSTER(bookLine); err != nil {
+		message = nil
+		book = nil
+		err = withLine(err, pr.messageReader.Line())
+		return
+	}
+	// Every following row must have as many levels as the first
+	pr.levels = book.Levels
+	return
+}
+
+// Next advances the reader to the next message and orderbook pair,
+// which are then available through Message and OrderBook. It returns
+// false when the end of the files is reached or an error occurs, after
+// which Err reports the error.
+func (pr *PairedReader) Next() bool {
+	if pr.err != nil {
+		return false
+	}
+	pr.currentMessage, pr.currentBook, pr.err = pr.Read()
+	return pr.err == nil
+}
+
+// Message returns the most recent message read by Next.
+func (pr *PairedReader) Message() LOBSTERData {
+	return pr.currentMessage
+}
+
+// OrderBook returns the most recent orderbook snapshot read by Next.
+func (pr *PairedReader) OrderBook() *OrderBookSnapshot {
+	return pr.currentBook
+}
+
+// Err returns the first error encountered by Next, or nil if Next
+// stopped because the end of the files was reached.
+func (pr *PairedReader) Err() error {
+	if pr.err == io.EOF {
+		return nil
+	}
+	return pr.err
+}
+
+// Line returns the line number of the most recently read pair of rows,
+// starting at 1 for the first row of each file.
+func (pr *PairedReader) Line() uint64 {
+	return pr.messageReader.Line()
+}
+
+// SetTradingDay sets the trading day the files were recorded on, which
+// Time uses to give the absolute time of each message.
+func (pr *PairedReader) SetTradingDay(tradingDay TradingDay) {
+	pr.messageReader.SetTradingDay(tradingDay)
+}
+
+// Time returns the absolute time of the most recently read message, or
+// the zero time if no trading day has been set.
+func (pr *PairedReader) Time() time.Time {
+	return pr.messageReader.Time()
+}
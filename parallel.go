@@ -0,0 +1,307 @@
+package lobsterdata
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// defaultChunkSize is the number of bytes of a message file parsed by
+// each job of a ParallelMessageReader if no chunk size is given.
+const defaultChunkSize = 4 << 20
+
+// ParallelOptions configures a ParallelMessageReader.
+type ParallelOptions struct {
+	// Workers is the number of goroutines parsing chunks. It defaults
+	// to the number of CPUs.
+	Workers int
+	// ChunkSize is the approximate number of bytes in each chunk. It
+	// defaults to 4 MiB.
+	ChunkSize int
+	// Unordered returns messages chunk by chunk in whichever order the
+	// chunks finish parsing, instead of in the order of the file. The
+	// rows within a chunk stay in order, and Line still reports the
+	// line of each message.
+	Unordered bool
+}
+
+// parsedRow is the result of parsing a single row of a chunk.
+type parsedRow struct {
+	line uint64
+	data LOBSTERData
+	err  error
+}
+
+// messageChunk is a run of whole rows of a message file that is parsed
+// by a single worker.
+type messageChunk struct {
+	index     uint64
+	startLine uint64
+	data      []byte
+	rows      []parsedRow
+	// err is an error reading the file, which ends it at this chunk
+	err error
+}
+
+// parse parses every row of the chunk, releasing its raw data.
+func (mc *messageChunk) parse() {
+	data := mc.data
+	line := mc.startLine
+	for len(data) > 0 {
+		var row []byte
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			row, data = data[:i], data[i+1:]
+		} else {
+			row, data = data, nil
+		}
+		if len(row) > 0 && row[len(row)-1] == '\r' {
+			row = row[:len(row)-1]
+		}
+
+		if len(row) > 0 {
+			message, err := UnmarshalCsvMessageBytes(row)
+			if err != nil {
+				err = withLine(err, line)
+			}
+			mc.rows = append(mc.rows, parsedRow{line: line, data: message, err: err})
+		}
+		line++
+	}
+	mc.data = nil
+}
+
+// ParallelMessageReader reads a LOBSTER message file by splitting it
+// into chunks at line boundaries and parsing the chunks concurrently
+// with MessageScanner's parser. Unlike the other readers, Line reports
+// the physical line of each row, which only differs from the row
+// number if the file contains empty lines.
+//
+// Close must be called if the reader is not read until the end, so
+// that its goroutines exit.
+type ParallelMessageReader struct {
+	options ParallelOptions
+	results chan *messageChunk
+	// tokens limits the number of chunks that are read but not yet
+	// returned, so that memory stays bounded when one chunk is slow
+	tokens    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	pending   map[uint64]*messageChunk
+	nextIndex uint64
+	chunk     *messageChunk
+	position  int
+
+	line          uint64
+	sinceMidnight time.Duration
+	tradingDay    TradingDay
+	current       LOBSTERData
+	failure       error
+	err           error
+}
+
+// NewParallelMessageReader returns a ParallelMessageReader that reads
+// LOBSTER message rows from r, and starts its goroutines.
+func NewParallelMessageReader(r io.Reader, options ParallelOptions) *ParallelMessageReader {
+	if options.Workers <= 0 {
+		options.Workers = runtime.NumCPU()
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = defaultChunkSize
+	}
+
+	pr := &ParallelMessageReader{
+		options: options,
+		results: make(chan *messageChunk, options.Workers),
+		tokens:  make(chan struct{}, 2*options.Workers),
+		done:    make(chan struct{}),
+		pending: make(map[uint64]*messageChunk),
+	}
+
+	jobs := make(chan *messageChunk, options.Workers)
+	go pr.produce(r, jobs)
+
+	var workers sync.WaitGroup
+	workers.Add(options.Workers)
+	for i := 0; i < options.Workers; i++ {
+		go func() {
+			defer workers.Done()
+			for chunk := range jobs {
+				chunk.parse()
+				select {
+				case pr.results <- chunk:
+				case <-pr.done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(pr.results)
+	}()
+	return pr
+}
+
+// produce splits the file into chunks that end at a newline and sends
+// them to the workers.
+func (pr *ParallelMessageReader) produce(r io.Reader, jobs chan<- *messageChunk) {
+	defer close(jobs)
+	var carry []byte
+	var index uint64
+	line := uint64(1)
+	for {
+		select {
+		case pr.tokens <- struct{}{}:
+		case <-pr.done:
+			return
+		}
+
+		buf := make([]byte, len(carry)+pr.options.ChunkSize)
+		copy(buf, carry)
+		n, err := io.ReadFull(r, buf[len(carry):])
+		buf = buf[:len(carry)+n]
+
+		chunk := &messageChunk{
+			index:     index,
+			startLine: line,
+		}
+		final := true
+		if err == nil {
+			// The partial line at the end is left for the next chunk
+			cut := bytes.LastIndexByte(buf, '\n') + 1
+			chunk.data, carry = buf[:cut], buf[cut:]
+			final = false
+		} else if err == io.EOF || err == io.ErrUnexpectedEOF {
+			chunk.data = buf
+		} else {
+			chunk.err = err
+		}
+		line += uint64(bytes.Count(chunk.data, []byte{'\n'}))
+		index++
+
+		select {
+		case jobs <- chunk:
+		case <-pr.done:
+			return
+		}
+		if final {
+			return
+		}
+	}
+}
+
+// nextChunk returns the next chunk to return rows from, or io.EOF
+// once every chunk has been returned.
+func (pr *ParallelMessageReader) nextChunk() (chunk *messageChunk, err error) {
+	for {
+		if chunk = pr.pending[pr.nextIndex]; chunk != nil {
+			delete(pr.pending, pr.nextIndex)
+			pr.nextIndex++
+			return
+		}
+
+		var ok bool
+		if chunk, ok = <-pr.results; !ok {
+			err = io.EOF
+			return
+		}
+		if pr.options.Unordered {
+			return
+		}
+		pr.pending[chunk.index] = chunk
+	}
+}
+
+// Read returns the next message. It returns io.EOF once there are no
+// rows left. An error decoding a single row does not prevent the
+// following rows from being read, but an error reading the file is
+// returned from every call after it.
+func (pr *ParallelMessageReader) Read() (data LOBSTERData, err error) {
+	if pr.failure != nil {
+		err = pr.failure
+		return
+	}
+
+	for pr.chunk == nil || pr.position >= len(pr.chunk.rows) {
+		if pr.chunk != nil {
+			pr.chunk = nil
+			<-pr.tokens
+		}
+		if pr.chunk, err = pr.nextChunk(); err != nil {
+			pr.failure = err
+			return
+		}
+		if pr.chunk.err != nil {
+			pr.failure = pr.chunk.err
+			err = pr.failure
+			pr.Close()
+			return
+		}
+		pr.position = 0
+	}
+
+	row := pr.chunk.rows[pr.position]
+	pr.position++
+	pr.line = row.line
+	if row.err == nil {
+		pr.sinceMidnight, _ = eventSinceMidnight(row.data)
+	}
+	return row.data, row.err
+}
+
+// Next advances the reader to the next message, which is then
+// available through Data. It returns false when the end of the file is
+// reached or an error occurs, after which Err reports the error.
+func (pr *ParallelMessageReader) Next() bool {
+	if pr.err != nil {
+		return false
+	}
+	pr.current, pr.err = pr.Read()
+	return pr.err == nil
+}
+
+// Data returns the most recent message read by Next.
+func (pr *ParallelMessageReader) Data() LOBSTERData {
+	return pr.current
+}
+
+// Err returns the first error encountered by Next, or nil if Next
+// stopped because the end of the file was reached.
+func (pr *ParallelMessageReader) Err() error {
+	if pr.err == io.EOF {
+		return nil
+	}
+	return pr.err
+}
+
+// Line returns the line number of the most recently read row, starting
+// at 1 for the first line of the file.
+func (pr *ParallelMessageReader) Line() uint64 {
+	return pr.line
+}
+
+// SetTradingDay sets the trading day the file was recorded on, which
+// Time uses to give the absolute time of each message.
+func (pr *ParallelMessageReader) SetTradingDay(tradingDay TradingDay) {
+	pr.tradingDay = tradingDay
+}
+
+// Time returns the absolute time of the most recently read message, or
+// the zero time if no trading day has been set.
+func (pr *ParallelMessageReader) Time() time.Time {
+	if pr.tradingDay.IsZero() {
+		return time.Time{}
+	}
+	return pr.tradingDay.Time(pr.sinceMidnight)
+}
+
+// Close stops the goroutines of the reader. Reading after Close may
+// return io.EOF early.
+func (pr *ParallelMessageReader) Close() {
+	pr.closeOnce.Do(func() {
+		close(pr.done)
+	})
+}
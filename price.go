@@ -0,0 +1,193 @@
+package lobsterdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Price is a LOBSTER price, which is a dollar price times 10000.
+type Price int64
+
+const (
+	// PriceScale is the number of Price units in one dollar.
+	PriceScale Price = 10000
+
+	// Cent is one cent, the usual tick size for stocks priced above
+	// one dollar.
+	Cent Price = 100
+)
+
+// priceDecimals is the number of decimal digits of a Price.
+const priceDecimals = 4
+
+// PriceFromDollars returns the Price closest to a dollar amount.
+func PriceFromDollars(dollars float64) Price {
+	return Price(math.Round(dollars * float64(PriceScale)))
+}
+
+// ParsePrice parses a decimal dollar amount such as "585.33" or
+// "585.3300" into a Price. Amounts with more than four decimals cannot
+// be represented and are rejected.
+func ParsePrice(dollars string) (price Price, err error) {
+	number := dollars
+	negative := strings.HasPrefix(number, "-")
+	number = strings.TrimPrefix(strings.TrimPrefix(number, "-"), "$")
+
+	whole, fraction := number, ""
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		whole, fraction = number[:i], number[i+1:]
+	}
+	if whole == "" && fraction == "" || len(fraction) > priceDecimals {
+		err = fmt.Errorf("Error parsing price %q, it must be a dollar amount with at most %d decimals", dollars, priceDecimals)
+		return
+	}
+
+	var wholeDollars, fractionUnits uint64
+	if whole != "" {
+		if wholeDollars, err = strconv.ParseUint(whole, 10, 63); err != nil {
+			err = fmt.Errorf("Error parsing dollars of price %q: %s", dollars, err)
+			return
+		}
+	}
+	if fraction != "" {
+		if fractionUnits, err = strconv.ParseUint(fraction, 10, 64); err != nil {
+			err = fmt.Errorf("Error parsing cents of price %q: %s", dollars, err)
+			return
+		}
+		for i := len(fraction); i < priceDecimals; i++ {
+			fractionUnits *= 10
+		}
+	}
+
+	maxDollars := uint64(math.MaxInt64 / PriceScale)
+	if wholeDollars > maxDollars || wholeDollars == maxDollars && fractionUnits > uint64(math.MaxInt64%PriceScale) {
+		err = fmt.Errorf("Error parsing price %q, it is out of range", dollars)
+		return
+	}
+	price = Price(wholeDollars)*PriceScale + Price(fractionUnits)
+	if negative {
+		price = -price
+	}
+	return
+}
+
+// Dollars returns the price in dollars.
+func (p Price) Dollars() float64 {
+	return float64(p) / float64(PriceScale)
+}
+
+// String formats the price in dollars with all four decimals, for
+// example "585.3300".
+func (p Price) String() string {
+	sign := ""
+	magnitude := uint64(p)
+	if p < 0 {
+		sign = "-"
+		magnitude = uint64(-p)
+	}
+	return fmt.Sprintf("%s%d.%04d", sign, magnitude/uint64(PriceScale), magnitude%uint64(PriceScale))
+}
+
+// UnmarshalJSON implements the JSONUnmarshaler interface, accepting
+// either an integer or a decimal dollar string such as "585.3300".
+func (p *Price) UnmarshalJSON(jsonBytes []byte) (err error) {
+	if len(jsonBytes) > 0 && jsonBytes[0] == '"' {
+		var dollars string
+		if err = json.Unmarshal(jsonBytes, &dollars); err != nil {
+			return
+		}
+		*p, err = ParsePrice(dollars)
+		return
+	}
+
+	var units int64
+	if err = json.Unmarshal(jsonBytes, &units); err != nil {
+		return
+	}
+	*p = Price(units)
+	return
+}
+
+// checkTick returns an error if a tick size is not positive, since no
+// price can be rounded to it.
+func checkTick(tick Price) (err error) {
+	if tick <= 0 {
+		err = fmt.Errorf("Error using tick size %s, it must be positive", tick)
+	}
+	return
+}
+
+// Ticks returns the number of whole ticks of the given size in the
+// price, rounding towards negative infinity. The tick size must be
+// positive.
+func (p Price) Ticks(tick Price) (ticks int64, err error) {
+	if err = checkTick(tick); err != nil {
+		return
+	}
+	ticks = int64(p / tick)
+	if p%tick != 0 && p < 0 {
+		ticks--
+	}
+	return
+}
+
+// RoundDown rounds the price down to a multiple of the tick size, which
+// must be positive.
+func (p Price) RoundDown(tick Price) (rounded Price, err error) {
+	var ticks int64
+	if ticks, err = p.Ticks(tick); err != nil {
+		return
+	}
+	rounded = Price(ticks) * tick
+	return
+}
+
+// RoundUp rounds the price up to a multiple of the tick size, which
+// must be positive.
+func (p Price) RoundUp(tick Price) (rounded Price, err error) {
+	if rounded, err = p.RoundDown(tick); err != nil {
+		return
+	}
+	if rounded != p {
+		rounded += tick
+	}
+	return
+}
+
+// Round rounds the price to the nearest multiple of the tick size,
+// which must be positive, rounding halfway prices up.
+func (p Price) Round(tick Price) (rounded Price, err error) {
+	if err = checkTick(tick); err != nil {
+		return
+	}
+	return (p + tick/2).RoundDown(tick)
+}
+
+// Spread returns the difference between an ask and a bid price.
+func Spread(bid Price, ask Price) Price {
+	return ask - bid
+}
+
+// SpreadTicks returns the difference between an ask and a bid price in
+// ticks of the given size, which must be positive.
+func SpreadTicks(bid Price, ask Price, tick Price) (ticks float64, err error) {
+	if err = checkTick(tick); err != nil {
+		return
+	}
+	ticks = float64(ask-bid) / float64(tick)
+	return
+}
+
+// parsePriceField parses the price column of a LOBSTER message row,
+// which must be a non-negative integer.
+func parsePriceField(field string) (price Price, err error) {
+	var units uint64
+	if units, err = strconv.ParseUint(field, 10, 63); err != nil {
+		return
+	}
+	price = Price(units)
+	return
+}
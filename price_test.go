@@ -0,0 +1,94 @@
+package lobsterdata
+
+import "testing"
+
+// parsePriceCases are dollar amounts and the Price ParsePrice must
+// return for them, or ok false if it must return an error.
+var parsePriceCases = []struct {
+	dollars string
+	price   Price
+	ok      bool
+}{
+	{"585.33", 5853300, true},
+	{"585.3300", 5853300, true},
+	{"$585.3", 5853000, true},
+	{".5", 5000, true},
+	{"12", 120000, true},
+	{"-0.0001", -1, true},
+	{"922337203685477.5807", 9223372036854775807, true},
+	{"-922337203685477.5807", -9223372036854775807, true},
+	{"", 0, false},
+	{".", 0, false},
+	{"585.33001", 0, false},
+	{"58x.33", 0, false},
+	{"585.3x", 0, false},
+	{"922337203685477.5808", 0, false},
+	{"922337203685477.9999", 0, false},
+	{"922337203685478", 0, false},
+}
+
+func TestParsePrice(t *testing.T) {
+	for _, c := range parsePriceCases {
+		price, err := ParsePrice(c.dollars)
+		if (err == nil) != c.ok {
+			t.Errorf("ParsePrice(%q): expected ok %t, got error %v", c.dollars, c.ok, err)
+			continue
+		}
+		if price != c.price {
+			t.Errorf("ParsePrice(%q): expected %d, got %d", c.dollars, c.price, price)
+		}
+	}
+}
+
+// roundCases are prices rounded to a tick size, and the results of
+// Ticks, RoundDown, RoundUp and Round.
+var roundCases = []struct {
+	price, tick       Price
+	ticks             int64
+	down, up, nearest Price
+}{
+	{5853300, Cent, 58533, 5853300, 5853300, 5853300},
+	{5853349, Cent, 58533, 5853300, 5853400, 5853300},
+	{5853350, Cent, 58533, 5853300, 5853400, 5853400},
+	{-5853349, Cent, -58534, -5853400, -5853300, -5853300},
+	{-5853350, Cent, -58534, -5853400, -5853300, -5853300},
+	{0, 5000, 0, 0, 0, 0},
+}
+
+func TestRound(t *testing.T) {
+	for _, c := range roundCases {
+		ticks, err := c.price.Ticks(c.tick)
+		if err != nil || ticks != c.ticks {
+			t.Errorf("%d.Ticks(%d): expected %d, got %d, %v", c.price, c.tick, c.ticks, ticks, err)
+		}
+		if down, err := c.price.RoundDown(c.tick); err != nil || down != c.down {
+			t.Errorf("%d.RoundDown(%d): expected %d, got %d, %v", c.price, c.tick, c.down, down, err)
+		}
+		if up, err := c.price.RoundUp(c.tick); err != nil || up != c.up {
+			t.Errorf("%d.RoundUp(%d): expected %d, got %d, %v", c.price, c.tick, c.up, up, err)
+		}
+		if nearest, err := c.price.Round(c.tick); err != nil || nearest != c.nearest {
+			t.Errorf("%d.Round(%d): expected %d, got %d, %v", c.price, c.tick, c.nearest, nearest, err)
+		}
+	}
+}
+
+func TestNonPositiveTick(t *testing.T) {
+	for _, tick := range []Price{0, -Cent} {
+		if _, err := Price(5853300).Ticks(tick); err == nil {
+			t.Errorf("Ticks(%d): expected an error", tick)
+		}
+		if _, err := Price(5853300).RoundDown(tick); err == nil {
+			t.Errorf("RoundDown(%d): expected an error", tick)
+		}
+		if _, err := Price(5853300).RoundUp(tick); err == nil {
+			t.Errorf("RoundUp(%d): expected an error", tick)
+		}
+		if _, err := Price(5853300).Round(tick); err == nil {
+			t.Errorf("Round(%d): expected an error", tick)
+		}
+		if _, err := SpreadTicks(5853300, 5853400, tick); err == nil {
+			t.Errorf("SpreadTicks(%d): expected an error", tick)
+		}
+	}
+}
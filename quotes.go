@@ -0,0 +1,227 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// BookLevels is implemented by both Book and OrderBookSnapshot, giving
+// access to the price levels of each side of an orderbook.
+type BookLevels interface {
+	// Bid returns the bid level at the given index, where index 0 is
+	// the best bid.
+	Bid(index int) (level PriceLevel, ok bool)
+	// Ask returns the ask level at the given index, where index 0 is
+	// the best ask.
+	Ask(index int) (level PriceLevel, ok bool)
+}
+
+// QuoteOptions configures how a Quote is computed.
+type QuoteOptions struct {
+	// TickSize is the tick size used for SpreadTicks, Cent if not
+	// set.
+	TickSize Price
+	// DepthLevels is the number of levels of each side used for
+	// DepthMid, 1 if not set.
+	DepthLevels int
+}
+
+// Quote is the state of the top of an orderbook at a point in time,
+// along with the prices commonly derived from it. Derived prices are
+// floats in the same units as Price, since they are not always whole
+// units.
+type Quote struct {
+	Time     time.Duration `json:"time"`
+	BidPrice Price         `json:"bidprice"`
+	BidSize  uint64        `json:"bidsize"`
+	AskPrice Price         `json:"askprice"`
+	AskSize  uint64        `json:"asksize"`
+	// Mid is halfway between the best bid and ask.
+	Mid float64 `json:"mid"`
+	// Spread is the difference between the best ask and bid.
+	Spread      Price   `json:"spread"`
+	SpreadTicks float64 `json:"spreadticks"`
+	// SpreadBps is the spread in basis points of Mid.
+	SpreadBps float64 `json:"spreadbps"`
+	// Microprice is the mid weighted by the imbalance of the best
+	// levels, which moves towards the ask when there is more size on
+	// the bid and the other way around.
+	Microprice float64 `json:"microprice"`
+	// DepthMid is halfway between the size weighted average prices of
+	// the first DepthLevels levels of each side.
+	DepthMid float64 `json:"depthmid"`
+}
+
+// NewQuote computes the Quote of an orderbook. The second return value
+// is false if either side of the orderbook is empty.
+func NewQuote(sinceMidnight time.Duration, book BookLevels, options QuoteOptions) (quote Quote, ok bool) {
+	if options.TickSize <= 0 {
+		options.TickSize = Cent
+	}
+	if options.DepthLevels <= 0 {
+		options.DepthLevels = 1
+	}
+
+	bid, bidOK := book.Bid(0)
+	ask, askOK := book.Ask(0)
+	if !bidOK || !askOK || bid.Empty() || ask.Empty() {
+		return
+	}
+
+	quote = Quote{
+		Time:     sinceMidnight,
+		BidPrice: bid.Price,
+		BidSize:  bid.Size,
+		AskPrice: ask.Price,
+		AskSize:  ask.Size,
+		Mid:      float64(bid.Price+ask.Price) / 2,
+		Spread:   Spread(bid.Price, ask.Price),
+	}
+	// TickSize was made positive above, so this cannot fail
+	quote.SpreadTicks, _ = SpreadTicks(bid.Price, ask.Price, options.TickSize)
+	if quote.Mid != 0 {
+		quote.SpreadBps = float64(quote.Spread) / quote.Mid * 10000
+	}
+	quote.Microprice = quote.Mid
+	if totalSize := bid.Size + ask.Size; totalSize > 0 {
+		quote.Microprice = (float64(bid.Price)*float64(ask.Size) + float64(ask.Price)*float64(bid.Size)) / float64(totalSize)
+	}
+	quote.DepthMid = (depthPrice(book.Bid, options.DepthLevels) + depthPrice(book.Ask, options.DepthLevels)) / 2
+	return quote, true
+}
+
+// depthPrice returns the size weighted average price of the first
+// levels of one side of an orderbook, which must have a non-empty
+// first level.
+func depthPrice(side func(index int) (PriceLevel, bool), levels int) float64 {
+	var notional float64
+	var size uint64
+	for i := 0; i < levels; i++ {
+		level, ok := side(i)
+		if !ok || level.Empty() {
+			break
+		}
+		notional += float64(level.Price) * float64(level.Size)
+		size += level.Size
+	}
+	if size == 0 {
+		first, _ := side(0)
+		return float64(first.Price)
+	}
+	return notional / float64(size)
+}
+
+// QuoteReader reads the Quote after every message of a LOBSTER message
+// file and its orderbook file. Messages after which either side of the
+// orderbook is empty have no Quote and are skipped.
+type QuoteReader struct {
+	Options QuoteOptions
+
+	pairedReader *PairedReader
+	current      Quote
+	err          error
+}
+
+// NewQuoteReader returns a QuoteReader that reads messages from
+// messages and orderbook snapshots from orderbook. If levels is 0, the
+// number of levels is inferred from the first orderbook row.
+func NewQuoteReader(messages io.Reader, orderbook io.Reader, levels int) *QuoteReader {
+	return &QuoteReader{
+		pairedReader: NewPairedReader(messages, orderbook, levels),
+	}
+}
+
+// Read returns the next Quote. It returns io.EOF once both files have
+// been read completely.
+func (qr *QuoteReader) Read() (quote Quote, err error) {
+	for {
+		var message LOBSTERData
+		var book *OrderBookSnapshot
+		if message, book, err = qr.pairedReader.Read(); err != nil {
+			return
+		}
+		sinceMidnight, _ := eventSinceMidnight(message)
+		var ok bool
+		if quote, ok = NewQuote(sinceMidnight, book, qr.Options); ok {
+			return
+		}
+	}
+}
+
+// Next advances the reader to the next Quote, which is then available
+// through Quote. It returns false when the end of the files is reached
+// or an error occurs, after which Err reports the error.
+func (qr *QuoteReader) Next() bool {
+	if qr.err != nil {
+		return false
+	}
+	qr.current, qr.err = qr.Read()
+	return qr.err == nil
+}
+
+// Quote returns the most recent Quote read by Next.
+func (qr *QuoteReader) Quote() Quote {
+	return qr.current
+}
+
+// Err returns the first error encountered by Next, or nil if Next
+// stopped because the end of the files was reached.
+func (qr *QuoteReader) Err() error {
+	if qr.err == io.EOF {
+		return nil
+	}
+	return qr.err
+}
+
+// Line returns the line number of the most recently read rows.
+func (qr *QuoteReader) Line() uint64 {
+	return qr.pairedReader.Line()
+}
+
+// QuoteSampler resamples a series of Quotes onto a clock grid of
+// multiples of an interval since midnight. Each grid point is given the
+// last Quote at or before it, with its Time set to the grid point.
+type QuoteSampler struct {
+	grid resampleGrid
+	last *Quote
+}
+
+// NewQuoteSampler returns a QuoteSampler with the given interval,
+// which must be positive.
+func NewQuoteSampler(interval time.Duration) (sampler *QuoteSampler, err error) {
+	if interval <= 0 {
+		err = fmt.Errorf("Error creating quote sampler, need a positive interval, not %s", interval)
+		return
+	}
+	sampler = &QuoteSampler{
+		grid: resampleGrid{interval: interval},
+	}
+	return
+}
+
+// Apply adds the next Quote of the series, returning the samples of
+// every grid point before it. Grid points before the first Quote are
+// not sampled.
+func (qs *QuoteSampler) Apply(quote Quote) (samples []Quote) {
+	qs.grid.start(quote.Time)
+	samples = qs.samplesBefore(quote.Time)
+	qs.last = &quote
+	return
+}
+
+// Flush returns the samples of every grid point up to and including
+// end, which is typically MarketClose.
+func (qs *QuoteSampler) Flush(end time.Duration) (samples []Quote) {
+	return qs.samplesBefore(end + 1)
+}
+
+// samplesBefore returns the samples of every grid point before t.
+func (qs *QuoteSampler) samplesBefore(t time.Duration) (samples []Quote) {
+	for _, point := range qs.grid.before(t) {
+		sample := *qs.last
+		sample.Time = point
+		samples = append(samples, sample)
+	}
+	return
+}
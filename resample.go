@@ -0,0 +1,159 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// MarketOpen is the time since midnight regular trading starts,
+	// 9:30.
+	MarketOpen = 34200 * time.Second
+	// MarketClose is the time since midnight regular trading ends,
+	// 16:00.
+	MarketClose = 57600 * time.Second
+)
+
+// resampleGrid generates the points of a clock grid, which are
+// offset plus multiples of interval.
+type resampleGrid struct {
+	interval time.Duration
+	offset   time.Duration
+	started  bool
+	next     time.Duration
+}
+
+// start makes the first grid point the first one at or after t, unless
+// the grid has already started. A grid without a positive interval
+// never starts.
+func (rg *resampleGrid) start(t time.Duration) {
+	if rg.started || rg.interval <= 0 {
+		return
+	}
+	rg.started = true
+	rg.next = t - (t-rg.offset)%rg.interval
+	if rg.next < t {
+		rg.next += rg.interval
+	}
+}
+
+// before returns every remaining grid point before t.
+func (rg *resampleGrid) before(t time.Duration) (points []time.Duration) {
+	if !rg.started {
+		return
+	}
+	for ; rg.next < t; rg.next += rg.interval {
+		points = append(points, rg.next)
+	}
+	return
+}
+
+// ResampleOptions configures the clock grid of a Resampler.
+type ResampleOptions struct {
+	// Interval is the time between grid points.
+	Interval time.Duration
+	// AlignToOpen puts grid points at MarketOpen plus multiples of
+	// Interval, instead of multiples of Interval since midnight. This
+	// only matters if Interval does not divide MarketOpen.
+	AlignToOpen bool
+}
+
+// Sample is the state of the orderbook at a grid point.
+type Sample struct {
+	Time      time.Duration      `json:"time"`
+	OrderBook *OrderBookSnapshot `json:"orderbook"`
+	// Phase is the phase of the trading session at the grid point.
+	Phase SessionPhase `json:"phase"`
+	// Halted is whether trading was halted at the grid point, even if
+	// it is outside regular trading hours.
+	Halted bool `json:"halted"`
+}
+
+// Resampler turns the orderbook snapshots after each LOBSTER message
+// into samples on a clock grid, where each grid point is given the last
+// snapshot at or before it. Grid points before the first message are
+// not sampled.
+type Resampler struct {
+	grid    resampleGrid
+	last    *OrderBookSnapshot
+	session *SessionState
+}
+
+// NewResampler returns a Resampler with the given options.
+func NewResampler(options ResampleOptions) (resampler *Resampler, err error) {
+	if options.Interval <= 0 {
+		err = fmt.Errorf("Error creating resampler, need a positive interval, not %s", options.Interval)
+		return
+	}
+	resampler = &Resampler{
+		grid:    resampleGrid{interval: options.Interval},
+		session: NewSessionState(),
+	}
+	if options.AlignToOpen {
+		resampler.grid.offset = MarketOpen
+	}
+	return
+}
+
+// Apply adds a message and the orderbook snapshot after it, returning
+// the samples of every grid point before the message. The snapshot
+// is kept until the next call and may be returned in several samples,
+// so it must not be modified afterwards. Trading halts are followed
+// with a SessionState that is not strict, to mark the samples taken
+// while trading is halted.
+func (r *Resampler) Apply(message LOBSTERData, book *OrderBookSnapshot) (samples []Sample, err error) {
+	sinceMidnight, ok := eventSinceMidnight(message)
+	if !ok {
+		err = fmt.Errorf("Error resampling LOBSTER orderbook, %T is not a LOBSTER message type", message)
+		return
+	}
+
+	r.grid.start(sinceMidnight)
+	samples = r.samplesBefore(sinceMidnight)
+
+	r.last = book
+	_, err = r.session.Apply(message)
+	return
+}
+
+// Flush returns the samples of every grid point up to and including
+// end, which is typically MarketClose.
+func (r *Resampler) Flush(end time.Duration) []Sample {
+	return r.samplesBefore(end + 1)
+}
+
+// samplesBefore returns the samples of every grid point before t.
+func (r *Resampler) samplesBefore(t time.Duration) (samples []Sample) {
+	for _, point := range r.grid.before(t) {
+		samples = append(samples, Sample{
+			Time:      point,
+			OrderBook: r.last,
+			Phase:     r.session.PhaseAt(point),
+			Halted:    r.session.TradingHalted(),
+		})
+	}
+	return
+}
+
+// Resample reads a LOBSTER message file and its orderbook file and
+// returns their samples on a clock grid up to MarketClose.
+func Resample(messages io.Reader, orderbook io.Reader, levels int, options ResampleOptions) (samples []Sample, err error) {
+	var resampler *Resampler
+	if resampler, err = NewResampler(options); err != nil {
+		return
+	}
+	pairedReader := NewPairedReader(messages, orderbook, levels)
+	for pairedReader.Next() {
+		var completed []Sample
+		if completed, err = resampler.Apply(pairedReader.Message(), pairedReader.OrderBook()); err != nil {
+			return
+		}
+		samples = append(samples, completed...)
+	}
+	if err = pairedReader.Err(); err != nil {
+		return
+	}
+	samples = append(samples, resampler.Flush(MarketClose)...)
+	return
+}
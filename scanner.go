@@ -0,0 +1,338 @@
+package lobsterdata
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"time"
+)
+
+// scannerBufferSize is the size of the buffer a MessageScanner reads
+// into, which must be larger than any single row.
+const scannerBufferSize = 1 << 16
+
+// eventsByDigit are the events indexed by their digit in the second
+// column of a message row, minus one.
+var eventsByDigit = [...]Event{
+	Submission,
+	Cancellation,
+	Deletion,
+	ExecutionVisible,
+	ExecutionHidden,
+	CrossTrade,
+	TradingHalt,
+}
+
+// MessageScanner reads rows from a LOBSTER message file like
+// MessageReader, but parses the raw bytes of each row directly instead
+// of going through encoding/csv. LOBSTER message files only contain
+// unquoted numeric fields, which is all MessageScanner supports.
+type MessageScanner struct {
+	reader        *bufio.Reader
+	data          []byte
+	line          uint64
+	current       LOBSTERData
+	err           error
+	sinceMidnight time.Duration
+	tradingDay    TradingDay
+}
+
+// NewMessageScanner returns a MessageScanner that reads LOBSTER message
+// rows from r.
+func NewMessageScanner(r io.Reader) *MessageScanner {
+	return &MessageScanner{
+		reader: bufio.NewReaderSize(r, scannerBufferSize),
+	}
+}
+
+// NewMessageScannerBytes returns a MessageScanner that reads LOBSTER
+// message rows from data, such as a memory-mapped file, without
+// copying it.
+func NewMessageScannerBytes(data []byte) *MessageScanner {
+	return &MessageScanner{
+		data: data,
+	}
+}
+
+// nextLine returns the next line without its line ending. The returned
+// slice is only valid until the next call.
+func (ms *MessageScanner) nextLine() (line []byte, err error) {
+	if ms.reader == nil {
+		if len(ms.data) == 0 {
+			err = io.EOF
+			return
+		}
+		if i := bytes.IndexByte(ms.data, '\n'); i >= 0 {
+			line, ms.data = ms.data[:i], ms.data[i+1:]
+		} else {
+			line, ms.data = ms.data, nil
+		}
+	} else {
+		if line, err = ms.reader.ReadSlice('\n'); err == nil {
+			line = line[:len(line)-1]
+		} else if err == io.EOF && len(line) > 0 {
+			// The last line does not need to end in a newline
+			err = nil
+		} else {
+			return
+		}
+	}
+
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return
+}
+
+// Read reads and decodes the next row of the message file. It returns
+// io.EOF once there are no rows left. An error decoding a single row
+// does not prevent the following rows from being read.
+func (ms *MessageScanner) Read() (data LOBSTERData, err error) {
+	var line []byte
+	// Empty lines are skipped, like encoding/csv does
+	for len(line) == 0 {
+		if line, err = ms.nextLine(); err != nil {
+			return
+		}
+	}
+	ms.line++
+
+	if data, err = UnmarshalCsvMessageBytes(line); err != nil {
+		err = withLine(err, ms.line)
+		return
+	}
+	ms.sinceMidnight, _ = eventSinceMidnight(data)
+	return
+}
+
+// Next advances the scanner to the next message, which is then
+// available through Data. It returns false when the end of the file is
+// reached or an error occurs, after which Err reports the error.
+func (ms *MessageScanner) Next() bool {
+	if ms.err != nil {
+		return false
+	}
+	ms.current, ms.err = ms.Read()
+	return ms.err == nil
+}
+
+// Data returns the most recent message read by Next.
+func (ms *MessageScanner) Data() LOBSTERData {
+	return ms.current
+}
+
+// Err returns the first error encountered by Next, or nil if Next
+// stopped because the end of the file was reached.
+func (ms *MessageScanner) Err() error {
+	if ms.err == io.EOF {
+		return nil
+	}
+	return ms.err
+}
+
+// Line returns the line number of the most recently read row, starting
+// at 1 for the first row of the file.
+func (ms *MessageScanner) Line() uint64 {
+	return ms.line
+}
+
+// SetTradingDay sets the trading day the file was recorded on, which
+// Time uses to give the absolute time of each message.
+func (ms *MessageScanner) SetTradingDay(tradingDay TradingDay) {
+	ms.tradingDay = tradingDay
+}
+
+// Time returns the absolute time of the most recently read message, or
+// the zero time if no trading day has been set.
+func (ms *MessageScanner) Time() time.Time {
+	if ms.tradingDay.IsZero() {
+		return time.Time{}
+	}
+	return ms.tradingDay.Time(ms.sinceMidnight)
+}
+
+// UnmarshalCsvMessageBytes unmarshals a single raw row of a LOBSTER
+// message file, without its line ending, into the LOBSTERData type
+// matching its event type. It accepts the same rows as
+// UnmarshalCsvMessage, but does not allocate anything other than the
+// returned message unless the row is invalid.
+func UnmarshalCsvMessageBytes(line []byte) (data LOBSTERData, err error) {
+	var eventFields [6][]byte
+	numFields := 0
+	start := 0
+	for i := 0; i <= len(line); i++ {
+		if i < len(line) && line[i] != ',' {
+			continue
+		}
+		if numFields == len(eventFields) {
+			err = columnCountError("", bytes.Count(line, []byte{','})+1, len(eventFields))
+			return
+		}
+		eventFields[numFields] = line[start:i]
+		numFields++
+		start = i + 1
+	}
+	if numFields != len(eventFields) {
+		err = columnCountError("", numFields, len(eventFields))
+		return
+	}
+
+	var event Event
+	if eventField := eventFields[1]; len(eventField) == 1 && eventField[0] >= '1' && int(eventField[0]-'1') < len(eventsByDigit) {
+		event = eventsByDigit[eventField[0]-'1']
+	} else {
+		err = bytesFieldError("", &eventFields, 1, ErrUnknownEvent)
+		return
+	}
+
+	sinceMidnight, err := parseTimestampBytes(eventFields[0])
+	if err != nil {
+		err = bytesFieldError(event, &eventFields, 0, numError("ParseTimestamp", eventFields[0], err))
+		return
+	}
+
+	var orderID, size uint64
+	var price Price
+	var direction int64
+	if orderID, err = parseUintBytes(eventFields[2]); err != nil {
+		err = bytesFieldError(event, &eventFields, 2, numError("ParseUint", eventFields[2], err))
+		return
+	}
+	if size, err = parseUintBytes(eventFields[3]); err != nil {
+		err = bytesFieldError(event, &eventFields, 3, numError("ParseUint", eventFields[3], err))
+		return
+	}
+	// Trading halts store their reason in the price column, which can
+	// be negative
+	if event == TradingHalt {
+		var haltType int64
+		if haltType, err = parseIntBytes(eventFields[4]); err != nil {
+			err = bytesFieldError(event, &eventFields, 4, numError("ParseInt", eventFields[4], err))
+			return
+		}
+		price = Price(haltType)
+	} else {
+		var units uint64
+		if units, err = parseUintBytes(eventFields[4]); err == nil && units > 1<<63-1 {
+			err = strconv.ErrRange
+		}
+		if err != nil {
+			err = bytesFieldError(event, &eventFields, 4, numError("ParseUint", eventFields[4], err))
+			return
+		}
+		price = Price(units)
+	}
+	if direction, err = parseIntBytes(eventFields[5]); err != nil {
+		err = bytesFieldError(event, &eventFields, 5, numError("ParseInt", eventFields[5], err))
+		return
+	}
+
+	if event != TradingHalt && !Side(direction).Valid() {
+		err = bytesFieldError(event, &eventFields, 5, ErrInvalidValue)
+		return
+	}
+
+	switch event {
+	case Submission:
+		data = &LOBSTERSubmission{EventSinceMidnight: sinceMidnight, OrderID: orderID, Size: size, Price: price, Direction: Side(direction)}
+	case Cancellation:
+		data = &LOBSTERCancellation{EventSinceMidnight: sinceMidnight, OrderID: orderID, Size: size, Price: price, Direction: Side(direction)}
+	case Deletion:
+		data = &LOBSTERDeletion{EventSinceMidnight: sinceMidnight, OrderID: orderID, Size: size, Price: price, Direction: Side(direction)}
+	case ExecutionVisible:
+		data = &LOBSTERExecutionVisible{EventSinceMidnight: sinceMidnight, OrderID: orderID, Size: size, Price: price, Direction: Side(direction)}
+	case ExecutionHidden:
+		if orderID != 0 {
+			err = bytesFieldError(event, &eventFields, 2, ErrInvalidValue)
+			return
+		}
+		data = &LOBSTERExecutionHidden{EventSinceMidnight: sinceMidnight, Size: size, Price: price, Direction: Side(direction)}
+	case CrossTrade:
+		data = &LOBSTERCrossTrade{EventSinceMidnight: sinceMidnight, OrderID: orderID, Size: size, Price: price, Direction: Side(direction)}
+	case TradingHalt:
+		if orderID != 0 {
+			err = bytesFieldError(event, &eventFields, 2, ErrInvalidValue)
+			return
+		}
+		if size != 0 {
+			err = bytesFieldError(event, &eventFields, 3, ErrInvalidValue)
+			return
+		}
+		if direction != -1 {
+			err = bytesFieldError(event, &eventFields, 5, ErrInvalidValue)
+			return
+		}
+		data = &LOBSTERTradingHalt{EventSinceMidnight: sinceMidnight, HaltType: HaltReason(price)}
+	}
+	return
+}
+
+// bytesFieldError returns a ParseError for the raw field of a LOBSTER
+// message row at the given 0-based index.
+func bytesFieldError(event Event, eventFields *[6][]byte, index int, err error) *ParseError {
+	return &ParseError{
+		Event:  event,
+		Column: index + 1,
+		Field:  messageFields[index],
+		Value:  string(eventFields[index]),
+		Err:    err,
+	}
+}
+
+// numError returns the error strconv would have returned for a field
+// that failed to parse.
+func numError(function string, field []byte, err error) error {
+	return &strconv.NumError{Func: function, Num: string(field), Err: err}
+}
+
+// parseUintBytes parses a decimal uint64 like strconv.ParseUint,
+// returning strconv.ErrSyntax or strconv.ErrRange if it is invalid.
+func parseUintBytes(field []byte) (value uint64, err error) {
+	if len(field) == 0 {
+		err = strconv.ErrSyntax
+		return
+	}
+	for _, c := range field {
+		if c < '0' || c > '9' {
+			err = strconv.ErrSyntax
+			return
+		}
+		if value > (1<<64-1)/10 {
+			err = strconv.ErrRange
+			return
+		}
+		next := value*10 + uint64(c-'0')
+		if next < value {
+			err = strconv.ErrRange
+			return
+		}
+		value = next
+	}
+	return
+}
+
+// parseIntBytes parses a decimal int64 like strconv.ParseInt,
+// returning strconv.ErrSyntax or strconv.ErrRange if it is invalid.
+func parseIntBytes(field []byte) (value int64, err error) {
+	negative := false
+	if len(field) > 0 && (field[0] == '-' || field[0] == '+') {
+		negative = field[0] == '-'
+		field = field[1:]
+	}
+
+	var magnitude uint64
+	if magnitude, err = parseUintBytes(field); err != nil {
+		return
+	}
+	if !negative && magnitude > 1<<63-1 || negative && magnitude > 1<<63 {
+		err = strconv.ErrRange
+		return
+	}
+
+	value = int64(magnitude)
+	if negative {
+		value = -value
+	}
+	return
+}
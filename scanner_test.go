@@ -0,0 +1,154 @@
+package lobsterdata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// unmarshalCases are message rows that UnmarshalCsvMessage and
+// UnmarshalCsvMessageBytes must agree on. column is the 1-based column
+// of the expected ParseError, -1 for a valid row.
+var unmarshalCases = []struct {
+	name   string
+	row    string
+	column int
+}{
+	{"submission", "34200.004241176,1,16113575,18,5853300,1", -1},
+	{"cancellation", "34200.201743485,2,16113575,8,5853300,1", -1},
+	{"deletion", "34200.201781758,3,16113575,10,5853300,1", -1},
+	{"visible execution", "34200.289331443,4,16120456,18,5859100,-1", -1},
+	{"hidden execution", "34200.29,5,0,50,5855000,1", -1},
+	{"cross trade", "34200.3,6,12345,100,5855000,-1", -1},
+	{"trading halt", "34200.31,7,0,0,-1,-1", -1},
+	{"resume quoting", "34200.32,7,0,0,0,-1", -1},
+	{"whole second timestamp", "34200,1,1,1,1,1", -1},
+	{"too few columns", "34200,1,1,1,1", 0},
+	{"too many columns", "34200,1,1,1,1,1,1", 0},
+	{"empty row", "", 0},
+	{"unknown event", "34200,8,1,1,1,1", 2},
+	{"empty event", "34200,,1,1,1,1", 2},
+	{"timestamp syntax", "34200.x,1,1,1,1,1", 1},
+	{"empty timestamp", ",1,1,1,1,1", 1},
+	{"negative timestamp", "-1,1,1,1,1,1", 1},
+	{"timestamp out of range", "99999999999,1,1,1,1,1", 1},
+	{"orderid syntax", "34200,1,x,1,1,1", 3},
+	{"negative orderid", "34200,1,-1,1,1,1", 3},
+	{"orderid out of range", "34200,1,18446744073709551616,1,1,1", 3},
+	{"size syntax", "34200,2,1,1.5,1,1", 4},
+	{"empty size", "34200,2,1,,1,1", 4},
+	{"price syntax", "34200,3,1,1,58.5,1", 5},
+	{"negative price", "34200,4,1,1,-5853300,1", 5},
+	{"price out of range", "34200,4,1,1,9223372036854775808,1", 5},
+	{"direction syntax", "34200,1,1,1,1,buy", 6},
+	{"direction value", "34200,1,1,1,1,0", 6},
+	{"hidden execution orderid", "34200,5,1,1,1,1", 3},
+	{"halt orderid", "34200,7,1,0,-1,-1", 3},
+	{"halt size", "34200,7,0,1,-1,-1", 4},
+	{"halt direction", "34200,7,0,0,-1,1", 6},
+	{"halt type syntax", "34200,7,0,0,x,-1", 5},
+}
+
+func TestUnmarshalCsvMessageBytes(t *testing.T) {
+	for _, c := range unmarshalCases {
+		t.Run(c.name, func(t *testing.T) {
+			expected, expectedErr := UnmarshalCsvMessage(strings.Split(c.row, ","))
+			actual, actualErr := UnmarshalCsvMessageBytes([]byte(c.row))
+
+			if c.column < 0 {
+				if expectedErr != nil || actualErr != nil {
+					t.Fatalf("expected no errors, got %v and %v", expectedErr, actualErr)
+				}
+				if !reflect.DeepEqual(expected, actual) {
+					t.Fatalf("expected %#v, got %#v", expected, actual)
+				}
+				return
+			}
+
+			var expectedParseErr, actualParseErr *ParseError
+			if !errors.As(expectedErr, &expectedParseErr) || !errors.As(actualErr, &actualParseErr) {
+				t.Fatalf("expected two ParseErrors, got %v and %v", expectedErr, actualErr)
+			}
+			if expectedParseErr.Column != c.column || actualParseErr.Column != c.column {
+				t.Fatalf("expected column %d, got %d and %d", c.column, expectedParseErr.Column, actualParseErr.Column)
+			}
+			if expectedErr.Error() != actualErr.Error() {
+				t.Fatalf("errors differ:\n%s\n%s", expectedErr, actualErr)
+			}
+			if actual != nil {
+				t.Fatalf("expected no message with an error, got %#v", actual)
+			}
+		})
+	}
+}
+
+// benchmarkRows returns a message file of n rows covering every event
+// type.
+func benchmarkRows(n int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		seconds := 34200 + i/1000
+		nanos := i % 1000 * 1000003
+		switch i % 10 {
+		case 0, 1, 2, 3:
+			fmt.Fprintf(&buf, "%d.%09d,1,%d,%d,%d,%d\n", seconds, nanos, 16000000+i, 100, 5853300+i%100*100, 1-2*(i%2))
+		case 4, 5:
+			fmt.Fprintf(&buf, "%d.%09d,2,%d,%d,%d,%d\n", seconds, nanos, 16000000+i, 50, 5853300, 1)
+		case 6, 7:
+			fmt.Fprintf(&buf, "%d.%09d,3,%d,%d,%d,%d\n", seconds, nanos, 16000000+i, 50, 5859100, -1)
+		case 8:
+			fmt.Fprintf(&buf, "%d.%09d,4,%d,%d,%d,%d\n", seconds, nanos, 16000000+i, 18, 5859100, -1)
+		case 9:
+			fmt.Fprintf(&buf, "%d.%09d,5,0,%d,%d,%d\n", seconds, nanos, 25, 5855000, 1)
+		}
+	}
+	return buf.Bytes()
+}
+
+// benchmarkRowCount is the number of rows parsed by each benchmark
+// iteration.
+const benchmarkRowCount = 10000
+
+func BenchmarkMessageReader(b *testing.B) {
+	data := benchmarkRows(benchmarkRowCount)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		messageReader := NewMessageReader(bytes.NewReader(data))
+		for messageReader.Next() {
+		}
+		if err := messageReader.Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMessageScanner(b *testing.B) {
+	data := benchmarkRows(benchmarkRowCount)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		messageScanner := NewMessageScanner(bytes.NewReader(data))
+		for messageScanner.Next() {
+		}
+		if err := messageScanner.Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalCsvMessageBytes(b *testing.B) {
+	lines := bytes.Split(bytes.TrimSuffix(benchmarkRows(benchmarkRowCount), []byte{'\n'}), []byte{'\n'})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalCsvMessageBytes(lines[i%len(lines)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
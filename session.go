@@ -0,0 +1,211 @@
+package lobsterdata
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrSessionTransition is returned by a strict SessionState when a
+// trading halt message does not follow from the state of the trading
+// session, such as trading resuming when it was never halted.
+var ErrSessionTransition = errors.New("LOBSTER trading halt is not a valid session transition")
+
+// SessionPhase represents the state of the trading session at a point
+// in time.
+type SessionPhase string
+
+const (
+	// PreOpen is any time before MarketOpen.
+	PreOpen SessionPhase = "preopen"
+	// Continuous means regular trading is taking place.
+	Continuous SessionPhase = "continuous"
+	// Halted means trading and quoting are halted.
+	Halted SessionPhase = "halted"
+	// QuoteOnly means quoting has resumed after a halt, but trading has
+	// not.
+	QuoteOnly SessionPhase = "quoteonly"
+	// PostClose is any time at or after MarketClose.
+	PostClose SessionPhase = "postclose"
+)
+
+// HaltInterval is a single trading halt.
+type HaltInterval struct {
+	// Start is the time trading was halted, or 0 if StartedBeforeData
+	// is set.
+	Start time.Duration `json:"start"`
+	// StartedBeforeData is set if trading was already halted when the
+	// data begins, so that the halt was not seen and only quoting or
+	// trading resuming was.
+	StartedBeforeData bool `json:"startedbeforedata"`
+	// QuotingResumed is the time quoting resumed before trading did,
+	// or 0 if they resumed together.
+	QuotingResumed time.Duration `json:"quotingresumed"`
+	// End is the time trading resumed, which is only set if Resumed
+	// is.
+	End     time.Duration `json:"end"`
+	Resumed bool          `json:"resumed"`
+}
+
+// SessionState follows the phase of the trading session through the
+// LOBSTER messages applied to it. Trading halt messages move the
+// session between Continuous, Halted and QuoteOnly, while PreOpen and
+// PostClose only depend on the time of day, whether or not trading is
+// halted.
+//
+// Halt messages that do not follow from the session are tolerated by
+// default, since a file can start while trading is already halted. A
+// resumption with no earlier halt is recorded as a halt that started
+// before the data, and a repeated halt or resumption is ignored.
+type SessionState struct {
+	// Strict returns an error for every trading halt message that does
+	// not follow from the session instead.
+	Strict bool
+
+	last      time.Duration
+	halted    bool
+	quoting   bool
+	intervals []HaltInterval
+}
+
+// NewSessionState returns a SessionState with trading not halted.
+func NewSessionState() *SessionState {
+	return &SessionState{}
+}
+
+// Apply updates the session with a LOBSTER message, returning the
+// phase of the session directly after it. If the session is strict, a
+// trading halt that is not a valid transition returns an error wrapping
+// ErrSessionTransition, and does not change the session.
+func (ss *SessionState) Apply(data LOBSTERData) (phase SessionPhase, err error) {
+	sinceMidnight, ok := eventSinceMidnight(data)
+	if !ok {
+		err = fmt.Errorf("Error following LOBSTER session, %T is not a LOBSTER message type", data)
+		return
+	}
+	ss.last = sinceMidnight
+
+	if halt, ok := data.(*LOBSTERTradingHalt); ok {
+		err = ss.transition(halt)
+	}
+	phase = ss.Phase()
+	return
+}
+
+// transition moves the session along for a trading halt message.
+func (ss *SessionState) transition(halt *LOBSTERTradingHalt) (err error) {
+	switch halt.HaltType {
+	case HaltTrading:
+		if ss.halted {
+			if ss.Strict {
+				err = fmt.Errorf("Error following LOBSTER session, trading halted at %s while already halted: %w", halt.EventSinceMidnight, ErrSessionTransition)
+			}
+			return
+		}
+		ss.halted = true
+		ss.intervals = append(ss.intervals, HaltInterval{Start: halt.EventSinceMidnight})
+	case ResumeQuoting:
+		if !ss.halted || ss.quoting {
+			if ss.Strict {
+				err = fmt.Errorf("Error following LOBSTER session, quoting resumed at %s while in phase %s: %w", halt.EventSinceMidnight, ss.haltPhase(), ErrSessionTransition)
+				return
+			}
+			if !ss.haltedBeforeData() {
+				return
+			}
+		}
+		ss.halted, ss.quoting = true, true
+		ss.intervals[len(ss.intervals)-1].QuotingResumed = halt.EventSinceMidnight
+	case ResumeTrading:
+		if !ss.halted {
+			if ss.Strict {
+				err = fmt.Errorf("Error following LOBSTER session, trading resumed at %s while not halted: %w", halt.EventSinceMidnight, ErrSessionTransition)
+				return
+			}
+			if !ss.haltedBeforeData() {
+				return
+			}
+		}
+		ss.halted, ss.quoting = false, false
+		interval := &ss.intervals[len(ss.intervals)-1]
+		interval.End = halt.EventSinceMidnight
+		interval.Resumed = true
+	default:
+		err = fmt.Errorf("Error following LOBSTER session, unknown halt type %d at %s", halt.HaltType, halt.EventSinceMidnight)
+	}
+	return
+}
+
+// haltedBeforeData records a halt that started before the data, if no
+// halt has been seen yet and trading is not halted, returning whether
+// it did. The resumption being applied then ends this halt.
+func (ss *SessionState) haltedBeforeData() bool {
+	if ss.halted || len(ss.intervals) > 0 {
+		return false
+	}
+	ss.intervals = append(ss.intervals, HaltInterval{StartedBeforeData: true})
+	return true
+}
+
+// haltPhase returns the phase of the session from the trading halts
+// alone, ignoring the time of day.
+func (ss *SessionState) haltPhase() SessionPhase {
+	switch {
+	case ss.quoting:
+		return QuoteOnly
+	case ss.halted:
+		return Halted
+	}
+	return Continuous
+}
+
+// Phase returns the phase of the session at the time of the most
+// recent message.
+func (ss *SessionState) Phase() SessionPhase {
+	return ss.PhaseAt(ss.last)
+}
+
+// PhaseAt returns the phase the session would be in at the given time,
+// if no more trading halt messages happened before it.
+func (ss *SessionState) PhaseAt(sinceMidnight time.Duration) SessionPhase {
+	switch {
+	case sinceMidnight < MarketOpen:
+		return PreOpen
+	case sinceMidnight >= MarketClose:
+		return PostClose
+	}
+	return ss.haltPhase()
+}
+
+// TradingHalted returns whether trading is halted, including while only
+// quoting has resumed, regardless of the time of day.
+func (ss *SessionState) TradingHalted() bool {
+	return ss.halted
+}
+
+// Intervals returns every trading halt seen so far, including one that
+// has not ended yet.
+func (ss *SessionState) Intervals() []HaltInterval {
+	return ss.intervals
+}
+
+// HaltIntervals reads every message of a LOBSTER message file and
+// returns its trading halts. Halt messages that do not follow from the
+// session are tolerated, as they are by a SessionState that is not
+// strict.
+func HaltIntervals(messages io.Reader) (intervals []HaltInterval, err error) {
+	messageReader := NewMessageReader(messages)
+	session := NewSessionState()
+	for messageReader.Next() {
+		if _, err = session.Apply(messageReader.Data()); err != nil {
+			err = fmt.Errorf("Error reading LOBSTER trading halts on line %d: %w", messageReader.Line(), err)
+			return
+		}
+	}
+	if err = messageReader.Err(); err != nil {
+		return
+	}
+	intervals = session.Intervals()
+	return
+}
@@ -0,0 +1,74 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Side represents the side of the book an order is on. Its values are
+// defined according to the Direction field in the LOBSTER csv.
+type Side int64
+
+const (
+	Buy  Side = 1
+	Sell Side = -1
+)
+
+// Valid returns whether the side is either Buy or Sell.
+func (s Side) Valid() bool {
+	return s == Buy || s == Sell
+}
+
+// Opposite returns the other side of the book.
+func (s Side) Opposite() Side {
+	return -s
+}
+
+// String returns "buy" or "sell".
+func (s Side) String() string {
+	switch s {
+	case Buy:
+		return "buy"
+	case Sell:
+		return "sell"
+	}
+	return fmt.Sprintf("Side(%d)", int64(s))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, so
+// that sides are written to JSON as "buy" or "sell".
+func (s Side) MarshalText() (text []byte, err error) {
+	if !s.Valid() {
+		err = fmt.Errorf("Error marshalling side %d, it is neither 1 nor -1", int64(s))
+		return
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface,
+// accepting "buy" or "sell".
+func (s *Side) UnmarshalText(text []byte) (err error) {
+	switch string(text) {
+	case "buy":
+		*s = Buy
+	case "sell":
+		*s = Sell
+	default:
+		err = fmt.Errorf("Error unmarshalling side %q, it is neither \"buy\" nor \"sell\"", text)
+	}
+	return
+}
+
+// parseSideField parses the direction column of a LOBSTER message row,
+// which must be either 1 or -1.
+func parseSideField(field string) (side Side, err error) {
+	var direction int64
+	if direction, err = strconv.ParseInt(field, 10, 64); err != nil {
+		return
+	}
+	if side = Side(direction); !side.Valid() {
+		err = ErrInvalidValue
+		return
+	}
+	return
+}
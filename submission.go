@@ -13,48 +13,45 @@ type LOBSTERSubmission struct {
 	EventSinceMidnight time.Duration `json:"timesincemidnight"`
 	OrderID            uint64        `json:"orderid"`
 	Size               uint64        `json:"size"`
-	Price              uint64        `json:"price"`
-	Direction          int64         `json:"side"`
+	Price              Price         `json:"price"`
+	Direction          Side          `json:"side"`
 }
 
 // UnmarshalCsvLOBSTER unmarshals a list of strings into a
 // LOBSTERSubmission, given they are parsed from encoding/csv.
 func (ls *LOBSTERSubmission) UnmarshalCsvLOBSTER(eventFields []string) (err error) {
 	if len(eventFields) != 6 {
-		err = fmt.Errorf("Error unmarshalling LOBSTER submission, data does not have 6 columns")
+		err = columnCountError(Submission, len(eventFields), 6)
 		return
 	}
 
 	if Event(eventFields[1]) != Submission {
-		err = fmt.Errorf("Trying to unmarshal a LOBSTER submission from an event that is not a submission is invalid")
+		err = messageFieldError(Submission, eventFields, 1, ErrEventType)
 		return
 	}
 
-	// Adding a "seconds" to the first field because we want to parse
-	// it as a duration
-	eventFields[0] += "s"
-	if ls.EventSinceMidnight, err = time.ParseDuration(eventFields[0]); err != nil {
-		err = fmt.Errorf("Error parsing the time field in LOBSTER data as a duration: %s", err)
+	if ls.EventSinceMidnight, err = ParseTimestamp(eventFields[0]); err != nil {
+		err = messageFieldError(Submission, eventFields, 0, err)
 		return
 	}
 
 	if ls.OrderID, err = strconv.ParseUint(eventFields[2], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing orderid field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(Submission, eventFields, 2, err)
 		return
 	}
 
 	if ls.Size, err = strconv.ParseUint(eventFields[3], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing size field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(Submission, eventFields, 3, err)
 		return
 	}
 
-	if ls.Price, err = strconv.ParseUint(eventFields[4], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing price field in LOBSTER data as uint64: %s", err)
+	if ls.Price, err = parsePriceField(eventFields[4]); err != nil {
+		err = messageFieldError(Submission, eventFields, 4, err)
 		return
 	}
 
-	if ls.Direction, err = strconv.ParseInt(eventFields[5], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing direction field in LOBSTER data as int64: %s", err)
+	if ls.Direction, err = parseSideField(eventFields[5]); err != nil {
+		err = messageFieldError(Submission, eventFields, 5, err)
 		return
 	}
 	return
@@ -64,7 +61,7 @@ func (ls *LOBSTERSubmission) UnmarshalCsvLOBSTER(eventFields []string) (err erro
 // that can be written using encoding/csv.
 func (ls *LOBSTERSubmission) MarshalCsvLOBSTER() (eventFields []string, err error) {
 	eventFields = make([]string, 6)
-	eventFields[0] = fmt.Sprintf("%f", ls.EventSinceMidnight.Seconds())
+	eventFields[0] = FormatTimestamp(ls.EventSinceMidnight, -1)
 	eventFields[1] = fmt.Sprintf("%s", Submission)
 	eventFields[2] = fmt.Sprintf("%d", ls.OrderID)
 	eventFields[3] = fmt.Sprintf("%d", ls.Size)
@@ -83,3 +80,55 @@ func (ls *LOBSTERSubmission) MarshalJSON() (jsonBytes []byte, err error) {
 		EventType:    Submission,
 	})
 }
+
+// UnmarshalJSON implements the JSONUnmarshaler interface for this
+// struct, reading the document written by MarshalJSON.
+func (ls *LOBSTERSubmission) UnmarshalJSON(jsonBytes []byte) (err error) {
+	// The event is decoded through a type without this method, so
+	// that it is not called recursively
+	type submission LOBSTERSubmission
+	var document struct {
+		TheMainEvent *submission `json:"event"`
+		EventType    Event       `json:"eventtype"`
+	}
+	document.TheMainEvent = (*submission)(ls)
+	if err = json.Unmarshal(jsonBytes, &document); err != nil {
+		return
+	}
+
+	if document.EventType != Submission {
+		err = fmt.Errorf("Error unmarshalling LOBSTER submission from JSON with event type %q: %w", document.EventType, ErrEventType)
+		return
+	}
+	return
+}
+
+// Type returns Submission, implementing LOBSTERMessage.
+func (ls *LOBSTERSubmission) Type() Event {
+	return Submission
+}
+
+// Time returns EventSinceMidnight, implementing LOBSTERMessage.
+func (ls *LOBSTERSubmission) Time() time.Duration {
+	return ls.EventSinceMidnight
+}
+
+// GetOrderID implements LOBSTERMessage.
+func (ls *LOBSTERSubmission) GetOrderID() uint64 {
+	return ls.OrderID
+}
+
+// GetSize implements LOBSTERMessage.
+func (ls *LOBSTERSubmission) GetSize() uint64 {
+	return ls.Size
+}
+
+// GetPrice implements LOBSTERMessage.
+func (ls *LOBSTERSubmission) GetPrice() Price {
+	return ls.Price
+}
+
+// Side implements LOBSTERMessage.
+func (ls *LOBSTERSubmission) Side() Side {
+	return ls.Direction
+}
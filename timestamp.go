@@ -0,0 +1,125 @@
+package lobsterdata
+
+import (
+	"strconv"
+	"time"
+)
+
+// maxTimestampSeconds is the largest number of whole seconds that fits
+// in a time.Duration.
+const maxTimestampSeconds = int64(1<<63-1) / int64(time.Second)
+
+// ParseTimestamp parses a time since midnight written as decimal
+// seconds, the way timestamps are written in LOBSTER message files.
+// The value is parsed exactly down to the nanosecond, and any further
+// digits are ignored. Unlike time.ParseDuration it does not need a
+// unit suffix, so it neither modifies nor copies its input.
+func ParseTimestamp(field string) (sinceMidnight time.Duration, err error) {
+	// Timestamps are short enough for this conversion to stay on the
+	// stack
+	if sinceMidnight, err = parseTimestampBytes([]byte(field)); err != nil {
+		err = &strconv.NumError{Func: "ParseTimestamp", Num: field, Err: err}
+		return
+	}
+	return
+}
+
+// parseTimestampBytes parses a timestamp like ParseTimestamp, returning
+// strconv.ErrSyntax or strconv.ErrRange if it is invalid.
+func parseTimestampBytes(field []byte) (sinceMidnight time.Duration, err error) {
+	var seconds, fraction int64
+	var digits, fractionDigits int
+	i := 0
+	for ; i < len(field) && field[i] >= '0' && field[i] <= '9'; i++ {
+		if seconds = seconds*10 + int64(field[i]-'0'); seconds > maxTimestampSeconds {
+			err = strconv.ErrRange
+			return
+		}
+		digits++
+	}
+
+	if i < len(field) && field[i] == '.' {
+		for i++; i < len(field) && field[i] >= '0' && field[i] <= '9'; i++ {
+			if fractionDigits < 9 {
+				fraction = fraction*10 + int64(field[i]-'0')
+				fractionDigits++
+			}
+			digits++
+		}
+	}
+
+	if digits == 0 || i != len(field) {
+		err = strconv.ErrSyntax
+		return
+	}
+
+	for ; fractionDigits < 9; fractionDigits++ {
+		fraction *= 10
+	}
+	if seconds == maxTimestampSeconds && fraction > int64(1<<63-1)%int64(time.Second) {
+		err = strconv.ErrRange
+		return
+	}
+	sinceMidnight = time.Duration(seconds*int64(time.Second) + fraction)
+	return
+}
+
+// FormatTimestamp formats a time since midnight as decimal seconds,
+// the way timestamps are written in LOBSTER message files. The
+// precision is the number of digits after the decimal point, and the
+// value is rounded to the nearest representable timestamp. A precision
+// of -1 uses the fewest digits that represent the timestamp exactly,
+// trimming trailing zeros like LOBSTER does, so that timestamps read
+// from a LOBSTER file are written back unchanged.
+func FormatTimestamp(sinceMidnight time.Duration, precision int) string {
+	buf := make([]byte, 0, 24)
+	nanos := int64(sinceMidnight)
+	if nanos < 0 {
+		buf = append(buf, '-')
+		nanos = -nanos
+	}
+
+	// Round to the requested number of digits if that drops any of
+	// the nine nanosecond digits
+	if precision >= 0 && precision < 9 {
+		unit := int64(1)
+		for i := precision; i < 9; i++ {
+			unit *= 10
+		}
+		nanos = (nanos + unit/2) / unit * unit
+	}
+
+	seconds := nanos / int64(time.Second)
+	fraction := nanos % int64(time.Second)
+	buf = strconv.AppendInt(buf, seconds, 10)
+
+	// Write all nine fractional digits, then cut them down to the
+	// requested precision
+	var digits [9]byte
+	for i := len(digits) - 1; i >= 0; i-- {
+		digits[i] = byte('0' + fraction%10)
+		fraction /= 10
+	}
+
+	numDigits := precision
+	if precision < 0 {
+		numDigits = len(digits)
+		for numDigits > 0 && digits[numDigits-1] == '0' {
+			numDigits--
+		}
+	}
+	if numDigits == 0 {
+		return string(buf)
+	}
+
+	buf = append(buf, '.')
+	if numDigits <= len(digits) {
+		buf = append(buf, digits[:numDigits]...)
+	} else {
+		buf = append(buf, digits[:]...)
+		for i := len(digits); i < numDigits; i++ {
+			buf = append(buf, '0')
+		}
+	}
+	return string(buf)
+}
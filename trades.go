@@ -0,0 +1,234 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// TradeKind represents which type of execution messages a Trade was
+// built from.
+type TradeKind string
+
+const (
+	// VisibleTrade is built from executions of visible limit orders,
+	// and possibly hidden ones as well, see Trade.HiddenSize.
+	VisibleTrade TradeKind = "visible"
+	// HiddenTrade is built only from executions of hidden limit orders.
+	HiddenTrade TradeKind = "hidden"
+	// AuctionTrade is an auction print, built from cross trades.
+	AuctionTrade TradeKind = "auction"
+)
+
+// Trade is a single marketable order, made up of every execution
+// against the same side of the book that shares a timestamp and is not
+// interrupted by any other message. Executions of visible and hidden
+// orders are grouped together, since one marketable order can fill
+// against both, while cross trades always make up trades of their own.
+type Trade struct {
+	Time time.Duration `json:"time"`
+	Kind TradeKind     `json:"kind"`
+	// Aggressor is the side of the marketable order, which is the
+	// opposite of the side of the resting orders it executed against.
+	// It is 0 for auction trades, which have no aggressor, and is then
+	// left out of JSON.
+	Aggressor Side   `json:"aggressor,omitempty"`
+	Size      uint64 `json:"size"`
+	// Notional is the sum of the price times the size of every
+	// execution, in the same units as Price.
+	Notional Price `json:"notional"`
+	// HiddenSize and HiddenNotional are the part of Size and Notional
+	// executed against hidden orders.
+	HiddenSize     uint64 `json:"hiddensize"`
+	HiddenNotional Price  `json:"hiddennotional"`
+	// VWAP is the volume weighted average price of the executions,
+	// rounded to the nearest unit.
+	VWAP       Price `json:"vwap"`
+	FirstPrice Price `json:"firstprice"`
+	LastPrice  Price `json:"lastprice"`
+	// Levels is the number of distinct price levels swept.
+	Levels int `json:"levels"`
+	// Executions is the number of execution messages in the trade.
+	Executions int `json:"executions"`
+
+	// visibleFirst and visibleLast are the prices of the first and last
+	// executions against visible orders, for bars without hidden
+	// executions
+	visibleFirst Price
+	visibleLast  Price
+}
+
+// add adds an execution to the trade.
+func (t *Trade) add(price Price, size uint64, hidden bool) {
+	if t.Executions == 0 || price != t.LastPrice {
+		t.Levels++
+	}
+	if t.Executions == 0 {
+		t.FirstPrice = price
+	}
+	t.LastPrice = price
+	t.Executions++
+	t.Size += size
+	t.Notional += price * Price(size)
+	if t.Size > 0 {
+		t.VWAP = (t.Notional + Price(t.Size/2)) / Price(t.Size)
+	}
+
+	if hidden {
+		t.HiddenSize += size
+		t.HiddenNotional += price * Price(size)
+		return
+	}
+	if t.Executions == 1 || t.Kind == HiddenTrade {
+		t.visibleFirst = price
+	}
+	if t.Kind == HiddenTrade {
+		t.Kind = VisibleTrade
+	}
+	t.visibleLast = price
+}
+
+// visiblePart returns the part of a trade executed against visible
+// orders. The trade must have at least one visible execution.
+func (t *Trade) visiblePart() *Trade {
+	visible := *t
+	visible.Size -= t.HiddenSize
+	visible.Notional -= t.HiddenNotional
+	visible.HiddenSize, visible.HiddenNotional = 0, 0
+	visible.FirstPrice, visible.LastPrice = t.visibleFirst, t.visibleLast
+	if visible.Size > 0 {
+		visible.VWAP = (visible.Notional + Price(visible.Size/2)) / Price(visible.Size)
+	}
+	return &visible
+}
+
+// TradeAggregator groups the execution messages applied to it into
+// Trades. Each LOBSTER execution is the fill of a single resting
+// order, so a marketable order that executes against several resting
+// orders shows up as several executions.
+type TradeAggregator struct {
+	current *Trade
+	// restingSide is the direction of the executions in current, which
+	// is needed for auction trades with no aggressor
+	restingSide Side
+}
+
+// NewTradeAggregator returns a TradeAggregator with no executions.
+func NewTradeAggregator() *TradeAggregator {
+	return &TradeAggregator{}
+}
+
+// Apply adds a LOBSTER message to the aggregator. If the message ends
+// the trade currently being built, that trade is returned, otherwise
+// the returned trade is nil. Any message that is not an execution of
+// the same trade ends it.
+func (ta *TradeAggregator) Apply(data LOBSTERData) (completed *Trade, err error) {
+	message, ok := data.(LOBSTERMessage)
+	if !ok {
+		err = fmt.Errorf("Error aggregating LOBSTER trades, %T is not a LOBSTER message type", data)
+		return
+	}
+
+	var kind TradeKind
+	var aggressor Side
+	switch message.Type() {
+	case ExecutionVisible:
+		kind, aggressor = VisibleTrade, message.Side().Opposite()
+	case ExecutionHidden:
+		kind, aggressor = HiddenTrade, message.Side().Opposite()
+	case CrossTrade:
+		kind = AuctionTrade
+	default:
+		return ta.Flush(), nil
+	}
+
+	if ta.current != nil && (ta.current.Time != message.Time() || (ta.current.Kind == AuctionTrade) != (kind == AuctionTrade) || ta.restingSide != message.Side()) {
+		completed = ta.Flush()
+	}
+	if ta.current == nil {
+		ta.current = &Trade{
+			Time:      message.Time(),
+			Kind:      kind,
+			Aggressor: aggressor,
+		}
+		ta.restingSide = message.Side()
+	}
+	ta.current.add(message.GetPrice(), message.GetSize(), kind == HiddenTrade)
+	return
+}
+
+// Flush returns the trade currently being built, or nil if there is
+// none. It should be called after the last message has been applied.
+func (ta *TradeAggregator) Flush() (trade *Trade) {
+	trade, ta.current = ta.current, nil
+	return
+}
+
+// TradeReader reads the Trades of a LOBSTER message file.
+type TradeReader struct {
+	messageReader *MessageReader
+	aggregator    *TradeAggregator
+	done          bool
+	current       *Trade
+	err           error
+}
+
+// NewTradeReader returns a TradeReader that reads LOBSTER message rows
+// from r.
+func NewTradeReader(r io.Reader) *TradeReader {
+	return &TradeReader{
+		messageReader: NewMessageReader(r),
+		aggregator:    NewTradeAggregator(),
+	}
+}
+
+// Read returns the next trade of the message file. It returns io.EOF
+// once there are no trades left.
+func (tr *TradeReader) Read() (trade *Trade, err error) {
+	for !tr.done {
+		if !tr.messageReader.Next() {
+			if err = tr.messageReader.Err(); err != nil {
+				return
+			}
+			tr.done = true
+			break
+		}
+		if trade, err = tr.aggregator.Apply(tr.messageReader.Data()); err != nil || trade != nil {
+			return
+		}
+	}
+	if trade = tr.aggregator.Flush(); trade == nil {
+		err = io.EOF
+	}
+	return
+}
+
+// Next advances the reader to the next trade, which is then available
+// through Trade. It returns false when the end of the file is reached
+// or an error occurs, after which Err reports the error.
+func (tr *TradeReader) Next() bool {
+	if tr.err != nil {
+		return false
+	}
+	tr.current, tr.err = tr.Read()
+	return tr.err == nil
+}
+
+// Trade returns the most recent trade read by Next.
+func (tr *TradeReader) Trade() *Trade {
+	return tr.current
+}
+
+// Err returns the first error encountered by Next, or nil if Next
+// stopped because the end of the file was reached.
+func (tr *TradeReader) Err() error {
+	if tr.err == io.EOF {
+		return nil
+	}
+	return tr.err
+}
+
+// Line returns the line number of the most recently read message row.
+func (tr *TradeReader) Line() uint64 {
+	return tr.messageReader.Line()
+}
@@ -0,0 +1,108 @@
+package lobsterdata
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExchangeTimezone is the timezone of the exchanges LOBSTER data is
+// recorded on, which all timestamps since midnight are relative to.
+const ExchangeTimezone = "America/New_York"
+
+// TradingDay turns the times since midnight of LOBSTER messages into
+// absolute times, given the date and timezone they were recorded in.
+type TradingDay struct {
+	year     int
+	month    time.Month
+	day      int
+	location *time.Location
+}
+
+// NewTradingDay returns a TradingDay for the calendar date of date, as
+// seen in its own location, with times interpreted in location. A nil
+// location means UTC.
+func NewTradingDay(date time.Time, location *time.Location) TradingDay {
+	if location == nil {
+		location = time.UTC
+	}
+	year, month, day := date.Date()
+	return TradingDay{
+		year:     year,
+		month:    month,
+		day:      day,
+		location: location,
+	}
+}
+
+// NewExchangeTradingDay returns a TradingDay for the given date in the
+// ExchangeTimezone, which requires the timezone database to be
+// installed.
+func NewExchangeTradingDay(date time.Time) (tradingDay TradingDay, err error) {
+	var location *time.Location
+	if location, err = time.LoadLocation(ExchangeTimezone); err != nil {
+		err = fmt.Errorf("Error loading LOBSTER exchange timezone %s: %s", ExchangeTimezone, err)
+		return
+	}
+	tradingDay = NewTradingDay(date, location)
+	return
+}
+
+// TradingDay returns the TradingDay of the dataset, in the
+// ExchangeTimezone.
+func (di DatasetInfo) TradingDay() (tradingDay TradingDay, err error) {
+	return NewExchangeTradingDay(di.Date)
+}
+
+// TradingDayFromFilename returns the TradingDay of a LOBSTER file,
+// inferred from its name.
+func TradingDayFromFilename(path string) (tradingDay TradingDay, err error) {
+	var info DatasetInfo
+	if info, err = ParseFilename(path); err != nil {
+		return
+	}
+	return info.TradingDay()
+}
+
+// IsZero returns whether the TradingDay is the zero value, which does
+// not refer to any date.
+func (td TradingDay) IsZero() bool {
+	return td.location == nil
+}
+
+// Date returns midnight at the start of the trading day, or the zero
+// time if the TradingDay is zero.
+func (td TradingDay) Date() time.Time {
+	if td.IsZero() {
+		return time.Time{}
+	}
+	return time.Date(td.year, td.month, td.day, 0, 0, 0, 0, td.location)
+}
+
+// Time returns the absolute time of a time since midnight. The time
+// since midnight is read off the wall clock, so times after a daylight
+// saving transition are still the expected clock time. The zero
+// TradingDay returns the zero time.
+func (td TradingDay) Time(sinceMidnight time.Duration) time.Time {
+	if td.IsZero() {
+		return time.Time{}
+	}
+	hours := sinceMidnight / time.Hour
+	minutes := sinceMidnight % time.Hour / time.Minute
+	seconds := sinceMidnight % time.Minute / time.Second
+	nanos := sinceMidnight % time.Second
+	return time.Date(td.year, td.month, td.day, int(hours), int(minutes), int(seconds), int(nanos), td.location)
+}
+
+// MessageTime returns the absolute time of a LOBSTER message. The
+// second return value is false if the TradingDay is zero or data is
+// not one of the message types.
+func (td TradingDay) MessageTime(data LOBSTERData) (messageTime time.Time, ok bool) {
+	if td.IsZero() {
+		return
+	}
+	var sinceMidnight time.Duration
+	if sinceMidnight, ok = eventSinceMidnight(data); ok {
+		messageTime = td.Time(sinceMidnight)
+	}
+	return
+}
@@ -0,0 +1,33 @@
+package lobsterdata
+
+import (
+	"testing"
+	"time"
+)
+
+func TestZeroTradingDay(t *testing.T) {
+	var tradingDay TradingDay
+	if !tradingDay.IsZero() {
+		t.Fatal("expected the zero TradingDay to be zero")
+	}
+	if date := tradingDay.Date(); !date.IsZero() {
+		t.Errorf("expected the zero date, got %s", date)
+	}
+	if messageTime := tradingDay.Time(MarketOpen); !messageTime.IsZero() {
+		t.Errorf("expected the zero time, got %s", messageTime)
+	}
+	if messageTime, ok := tradingDay.MessageTime(&LOBSTERSubmission{EventSinceMidnight: MarketOpen}); ok || !messageTime.IsZero() {
+		t.Errorf("expected no message time, got %s, %t", messageTime, ok)
+	}
+}
+
+func TestNewTradingDayWithoutLocation(t *testing.T) {
+	tradingDay := NewTradingDay(time.Date(2012, 6, 21, 0, 0, 0, 0, time.UTC), nil)
+	if tradingDay.IsZero() {
+		t.Fatal("expected a TradingDay that is not zero")
+	}
+	expected := time.Date(2012, 6, 21, 9, 30, 0, 0, time.UTC)
+	if messageTime, ok := tradingDay.MessageTime(&LOBSTERSubmission{EventSinceMidnight: MarketOpen}); !ok || !messageTime.Equal(expected) {
+		t.Errorf("expected %s, got %s, %t", expected, messageTime, ok)
+	}
+}
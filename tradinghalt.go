@@ -18,57 +18,54 @@ type LOBSTERTradingHalt struct {
 // LOBSTERTradingHalt, given they are parsed from encoding/csv.
 func (lth *LOBSTERTradingHalt) UnmarshalCsvLOBSTER(eventFields []string) (err error) {
 	if len(eventFields) != 6 {
-		err = fmt.Errorf("Error unmarshalling LOBSTER line, data does not have 6 columns")
+		err = columnCountError(TradingHalt, len(eventFields), 6)
 		return
 	}
 
 	if Event(eventFields[1]) != TradingHalt {
-		err = fmt.Errorf("Trying to unmarshal a LOBSTER trading halt from an event that is not a trading halt is invalid")
+		err = messageFieldError(TradingHalt, eventFields, 1, ErrEventType)
 		return
 	}
 
-	// Adding a "seconds" to the first field because we want to parse
-	// it as a duration
-	eventFields[0] += "s"
-	if lth.EventSinceMidnight, err = time.ParseDuration(eventFields[0]); err != nil {
-		err = fmt.Errorf("Error parsing the time field in LOBSTER data as a duration: %s", err)
+	if lth.EventSinceMidnight, err = ParseTimestamp(eventFields[0]); err != nil {
+		err = messageFieldError(TradingHalt, eventFields, 0, err)
 		return
 	}
 
 	var tmpOrderID uint64
 	if tmpOrderID, err = strconv.ParseUint(eventFields[2], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing orderid field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(TradingHalt, eventFields, 2, err)
 		return
 	}
 	if tmpOrderID != 0 {
-		err = fmt.Errorf("Invalid trading halt, orderid field must be 0")
+		err = messageFieldError(TradingHalt, eventFields, 2, ErrInvalidValue)
 		return
 	}
 
 	var tmpSize uint64
 	if tmpSize, err = strconv.ParseUint(eventFields[3], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing size field in LOBSTER data as uint64: %s", err)
+		err = messageFieldError(TradingHalt, eventFields, 3, err)
 		return
 	}
 	if tmpSize != 0 {
-		err = fmt.Errorf("Invalid trading halt, size field must be 0")
+		err = messageFieldError(TradingHalt, eventFields, 3, ErrInvalidValue)
 		return
 	}
 
 	var tmpHaltType int64
 	if tmpHaltType, err = strconv.ParseInt(eventFields[4], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing price field in LOBSTER data as int64: %s", err)
+		err = messageFieldError(TradingHalt, eventFields, 4, err)
 		return
 	}
 	lth.HaltType = HaltReason(tmpHaltType)
 
 	var tmpDirection int64
 	if tmpDirection, err = strconv.ParseInt(eventFields[5], 10, 64); err != nil {
-		err = fmt.Errorf("Error parsing direction field in LOBSTER data as int64: %s", err)
+		err = messageFieldError(TradingHalt, eventFields, 5, err)
 		return
 	}
 	if tmpDirection != -1 {
-		err = fmt.Errorf("Invalid trading halt, direction field must be -1")
+		err = messageFieldError(TradingHalt, eventFields, 5, ErrInvalidValue)
 		return
 	}
 	return
@@ -78,7 +75,7 @@ func (lth *LOBSTERTradingHalt) UnmarshalCsvLOBSTER(eventFields []string) (err er
 // that can be written using encoding/csv.
 func (lth *LOBSTERTradingHalt) MarshalCsvLOBSTER() (eventFields []string, err error) {
 	eventFields = make([]string, 6)
-	eventFields[0] = fmt.Sprintf("%f", lth.EventSinceMidnight.Seconds())
+	eventFields[0] = FormatTimestamp(lth.EventSinceMidnight, -1)
 	eventFields[1] = fmt.Sprintf("%s", TradingHalt)
 	eventFields[2] = "0"
 	eventFields[3] = "0"
@@ -97,3 +94,55 @@ func (lth *LOBSTERTradingHalt) MarshalJSON() (jsonBytes []byte, err error) {
 		EventType:    TradingHalt,
 	})
 }
+
+// UnmarshalJSON implements the JSONUnmarshaler interface for this
+// struct, reading the document written by MarshalJSON.
+func (lth *LOBSTERTradingHalt) UnmarshalJSON(jsonBytes []byte) (err error) {
+	// The event is decoded through a type without this method, so
+	// that it is not called recursively
+	type tradingHalt LOBSTERTradingHalt
+	var document struct {
+		TheMainEvent *tradingHalt `json:"event"`
+		EventType    Event        `json:"eventtype"`
+	}
+	document.TheMainEvent = (*tradingHalt)(lth)
+	if err = json.Unmarshal(jsonBytes, &document); err != nil {
+		return
+	}
+
+	if document.EventType != TradingHalt {
+		err = fmt.Errorf("Error unmarshalling LOBSTER trading halt from JSON with event type %q: %w", document.EventType, ErrEventType)
+		return
+	}
+	return
+}
+
+// Type returns TradingHalt, implementing LOBSTERMessage.
+func (lth *LOBSTERTradingHalt) Type() Event {
+	return TradingHalt
+}
+
+// Time returns EventSinceMidnight, implementing LOBSTERMessage.
+func (lth *LOBSTERTradingHalt) Time() time.Duration {
+	return lth.EventSinceMidnight
+}
+
+// GetOrderID implements LOBSTERMessage.
+func (lth *LOBSTERTradingHalt) GetOrderID() uint64 {
+	return 0
+}
+
+// GetSize implements LOBSTERMessage.
+func (lth *LOBSTERTradingHalt) GetSize() uint64 {
+	return 0
+}
+
+// GetPrice implements LOBSTERMessage.
+func (lth *LOBSTERTradingHalt) GetPrice() Price {
+	return 0
+}
+
+// Side implements LOBSTERMessage.
+func (lth *LOBSTERTradingHalt) Side() Side {
+	return 0
+}
@@ -0,0 +1,248 @@
+package lobsterdata
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// AnomalyKind represents how a LOBSTER message and the orderbook
+// snapshot after it disagree.
+type AnomalyKind string
+
+const (
+	// SizeMismatch means the size at the message's price level did
+	// not change by the size of the message.
+	SizeMismatch AnomalyKind = "sizemismatch"
+	// MissingLevel means the message removes size from a price level
+	// that is not in the orderbook.
+	MissingLevel AnomalyKind = "missinglevel"
+	// NonBestExecution means a visible execution happened at a price
+	// other than the best price on its side.
+	NonBestExecution AnomalyKind = "nonbestexecution"
+	// UnexpectedChange means a price level the message does not touch
+	// changed.
+	UnexpectedChange AnomalyKind = "unexpectedchange"
+	// UnparseableRow means the message or orderbook row could not be
+	// parsed, so it could not be checked.
+	UnparseableRow AnomalyKind = "unparseablerow"
+)
+
+// Anomaly is a single inconsistency between a LOBSTER message and the
+// orderbook snapshots before and after it.
+type Anomaly struct {
+	Line      uint64      `json:"line"`
+	EventType Event       `json:"eventtype"`
+	Kind      AnomalyKind `json:"kind"`
+	Price     Price       `json:"price"`
+	Direction Side        `json:"side,omitempty"`
+	Expected  uint64      `json:"expected"`
+	Actual    uint64      `json:"actual"`
+	// Error is the parse error of an UnparseableRow.
+	Error string `json:"error,omitempty"`
+}
+
+// String returns a human readable description of the anomaly.
+func (a Anomaly) String() string {
+	if a.Kind == UnparseableRow {
+		return fmt.Sprintf("line %d: %s: %s", a.Line, a.Kind, a.Error)
+	}
+	return fmt.Sprintf("line %d: event type %s: %s at price %s side %s, expected size %d but found %d", a.Line, a.EventType, a.Kind, a.Price, a.Direction, a.Expected, a.Actual)
+}
+
+// snapshotSide is one side of an orderbook snapshot.
+type snapshotSide struct {
+	levels []PriceLevel
+	// better returns whether price a is better than price b on this
+	// side
+	better func(a, b Price) bool
+}
+
+// size returns the size at the given price, or 0 if there is no such
+// level in the snapshot.
+func (ss snapshotSide) size(price Price) (size uint64, ok bool) {
+	for _, level := range ss.levels {
+		if !level.Empty() && level.Price == price {
+			return level.Size, true
+		}
+	}
+	return
+}
+
+// visible returns whether a level at the given price would be part of
+// the snapshot, because either the side has fewer levels than the
+// snapshot depth or the price is no worse than its worst level.
+func (ss snapshotSide) visible(price Price) bool {
+	var worst PriceLevel
+	count := 0
+	for _, level := range ss.levels {
+		if !level.Empty() {
+			worst = level
+			count++
+		}
+	}
+	return count < len(ss.levels) || !ss.better(worst.Price, price)
+}
+
+// Validator checks that each LOBSTER message changes the orderbook the
+// way it should, by comparing the orderbook snapshots before and after
+// it. Only price levels that are part of both snapshots can be
+// checked.
+type Validator struct {
+	previous *OrderBookSnapshot
+	line     uint64
+}
+
+// NewValidator returns a Validator with no previous snapshot.
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// Check validates a message against the previous snapshot passed to
+// Check and the snapshot after the message, returning every anomaly
+// found. The first message cannot be validated, since the state of
+// the orderbook before it is unknown. The levels of the snapshot are
+// copied, so the snapshot may be reused for the next row.
+func (v *Validator) Check(message LOBSTERData, book *OrderBookSnapshot) (anomalies []Anomaly) {
+	v.line++
+	previous := v.previous
+	v.previous = &OrderBookSnapshot{
+		Levels: book.Levels,
+		Asks:   append([]PriceLevel(nil), book.Asks...),
+		Bids:   append([]PriceLevel(nil), book.Bids...),
+	}
+	if previous == nil {
+		return
+	}
+
+	var eventType Event
+	var price Price
+	var direction Side
+	var size uint64
+	var removes bool
+	if m, ok := message.(LOBSTERMessage); ok {
+		eventType = m.Type()
+		switch eventType {
+		case Submission, Cancellation, Deletion, ExecutionVisible:
+			price, direction, size = m.GetPrice(), m.Side(), m.GetSize()
+			removes = eventType != Submission
+		}
+	}
+
+	bidsBetter := func(a, b Price) bool { return a > b }
+	asksBetter := func(a, b Price) bool { return a < b }
+	sides := []struct {
+		direction      Side
+		previous, next snapshotSide
+	}{
+		{Buy, snapshotSide{previous.Bids, bidsBetter}, snapshotSide{book.Bids, bidsBetter}},
+		{Sell, snapshotSide{previous.Asks, asksBetter}, snapshotSide{book.Asks, asksBetter}},
+	}
+
+	for _, side := range sides {
+		touched := size > 0 && side.direction == direction
+		if touched && eventType == ExecutionVisible && len(side.previous.levels) > 0 {
+			if best := side.previous.levels[0]; !best.Empty() && best.Price != price {
+				anomalies = append(anomalies, Anomaly{
+					Line:      v.line,
+					EventType: eventType,
+					Kind:      NonBestExecution,
+					Price:     price,
+					Direction: direction,
+				})
+			}
+		}
+
+		// Every price in either snapshot, plus the message's price,
+		// is checked if it is visible in both snapshots
+		var prices []Price
+		seen := make(map[Price]bool)
+		for _, levels := range [][]PriceLevel{side.previous.levels, side.next.levels} {
+			for _, level := range levels {
+				if !level.Empty() && !seen[level.Price] {
+					seen[level.Price] = true
+					prices = append(prices, level.Price)
+				}
+			}
+		}
+		if touched && !seen[price] {
+			prices = append(prices, price)
+		}
+
+		for _, levelPrice := range prices {
+			if !side.previous.visible(levelPrice) || !side.next.visible(levelPrice) {
+				continue
+			}
+			previousSize, found := side.previous.size(levelPrice)
+			actual, _ := side.next.size(levelPrice)
+
+			kind := UnexpectedChange
+			expected := previousSize
+			if touched && levelPrice == price {
+				kind = SizeMismatch
+				if !removes {
+					expected = previousSize + size
+				} else if !found {
+					kind = MissingLevel
+					expected = 0
+				} else if size <= previousSize {
+					expected = previousSize - size
+				} else {
+					expected = 0
+				}
+			}
+
+			if expected != actual || kind == MissingLevel {
+				anomalies = append(anomalies, Anomaly{
+					Line:      v.line,
+					EventType: eventType,
+					Kind:      kind,
+					Price:     levelPrice,
+					Direction: side.direction,
+					Expected:  expected,
+					Actual:    actual,
+				})
+			}
+		}
+	}
+	return
+}
+
+// Skip counts a row that could not be read, so that the row after it
+// is not compared with the snapshot before it and the lines of later
+// anomalies stay correct.
+func (v *Validator) Skip() {
+	v.line++
+	v.previous = nil
+}
+
+// Validate reads a LOBSTER message file and its orderbook file and
+// returns every anomaly found by a Validator. If levels is 0, the
+// number of levels is inferred from the orderbook file. Rows that fail
+// to parse are reported as UnparseableRow anomalies, while any other
+// error stops the validation.
+func Validate(messages io.Reader, orderbook io.Reader, levels int) (anomalies []Anomaly, err error) {
+	pairedReader := NewPairedReader(messages, orderbook, levels)
+	validator := NewValidator()
+	for {
+		message, book, readErr := pairedReader.Read()
+		var parseError *ParseError
+		switch {
+		case readErr == io.EOF:
+			return
+		case errors.As(readErr, &parseError):
+			validator.Skip()
+			anomalies = append(anomalies, Anomaly{
+				Line:      pairedReader.Line(),
+				EventType: parseError.Event,
+				Kind:      UnparseableRow,
+				Error:     readErr.Error(),
+			})
+		case readErr != nil:
+			err = readErr
+			return
+		default:
+			anomalies = append(anomalies, validator.Check(message, book)...)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package lobsterdata
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// validateBook is the first orderbook row of every validateCases
+// entry, with two levels and room for another bid.
+const validateBook = "1010000,200,1000000,100,1020000,300,-9999999999,0\n"
+
+// validateCases are paired message and orderbook files, and the
+// anomalies Validate must find in them. The first row only sets up the
+// orderbook, since it cannot be validated.
+var validateCases = []struct {
+	name      string
+	messages  string
+	orderbook string
+	anomalies []Anomaly
+}{
+	{
+		name: "consistent day",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,2,50,990000,1\n" +
+			"34200.3,2,1,40,1000000,1\n" +
+			"34200.4,4,9,200,1010000,-1\n" +
+			"34200.5,5,0,10,1005000,1\n" +
+			"34200.6,3,2,50,990000,1\n" +
+			"34200.7,1,3,10,1050000,-1\n" +
+			"34200.8,7,0,0,-1,-1\n",
+		orderbook: validateBook +
+			"1010000,200,1000000,100,1020000,300,990000,50\n" +
+			"1010000,200,1000000,60,1020000,300,990000,50\n" +
+			"1020000,300,1000000,60,9999999999,0,990000,50\n" +
+			"1020000,300,1000000,60,9999999999,0,990000,50\n" +
+			"1020000,300,1000000,60,9999999999,0,-9999999999,0\n" +
+			"1020000,300,1000000,60,1050000,10,-9999999999,0\n" +
+			"1020000,300,1000000,60,1050000,10,-9999999999,0\n",
+	},
+	{
+		name: "submission outside the snapshot",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,2,50,1020000,-1\n" +
+			"34200.3,1,3,50,1030000,-1\n",
+		orderbook: validateBook +
+			"1010000,200,1000000,100,1020000,350,-9999999999,0\n" +
+			"1010000,200,1000000,100,1020000,350,-9999999999,0\n",
+	},
+	{
+		name: "size mismatch",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,2,100,1000000,1\n",
+		orderbook: validateBook +
+			"1010000,200,1000000,150,1020000,300,-9999999999,0\n",
+		anomalies: []Anomaly{
+			{Line: 2, EventType: Submission, Kind: SizeMismatch, Price: 1000000, Direction: Buy, Expected: 200, Actual: 150},
+		},
+	},
+	{
+		name: "missing level",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,2,5,10,995000,1\n",
+		orderbook: validateBook + validateBook,
+		anomalies: []Anomaly{
+			{Line: 2, EventType: Cancellation, Kind: MissingLevel, Price: 995000, Direction: Buy},
+		},
+	},
+	{
+		name: "non best execution",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,4,5,300,1020000,-1\n",
+		orderbook: validateBook +
+			"1010000,200,1000000,100,9999999999,0,-9999999999,0\n",
+		anomalies: []Anomaly{
+			{Line: 2, EventType: ExecutionVisible, Kind: NonBestExecution, Price: 1020000, Direction: Sell},
+		},
+	},
+	{
+		name: "unexpected change",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,2,50,1000000,1\n",
+		orderbook: validateBook +
+			"1010000,150,1000000,150,1020000,300,-9999999999,0\n",
+		anomalies: []Anomaly{
+			{Line: 2, EventType: Submission, Kind: UnexpectedChange, Price: 1010000, Direction: Sell, Expected: 200, Actual: 150},
+		},
+	},
+	{
+		name: "unparseable row",
+		messages: "34200.1,1,1,100,1000000,1\n" +
+			"34200.2,1,x,50,1000000,1\n" +
+			"34200.3,2,1,10,1000000,1\n",
+		orderbook: validateBook +
+			"1010000,200,1000000,150,1020000,300,-9999999999,0\n" +
+			"1010000,200,1000000,140,1020000,300,-9999999999,0\n",
+		anomalies: []Anomaly{
+			{Line: 2, EventType: Submission, Kind: UnparseableRow},
+		},
+	},
+}
+
+func TestValidate(t *testing.T) {
+	for _, c := range validateCases {
+		t.Run(c.name, func(t *testing.T) {
+			anomalies, err := Validate(strings.NewReader(c.messages), strings.NewReader(c.orderbook), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range anomalies {
+				if anomalies[i].Kind == UnparseableRow {
+					if anomalies[i].Error == "" {
+						t.Errorf("expected the parse error of anomaly %+v", anomalies[i])
+					}
+					anomalies[i].Error = ""
+				}
+			}
+			if !reflect.DeepEqual(c.anomalies, anomalies) {
+				t.Fatalf("expected anomalies %+v, got %+v", c.anomalies, anomalies)
+			}
+		})
+	}
+}